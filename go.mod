@@ -1,5 +1,5 @@
 module github.com/lucas-s-work/funcy-go
 
-go 1.18
+go 1.23
 
-require golang.org/x/exp v0.0.0-20220706164943-b4a6d9510983 // indirect
+require golang.org/x/exp v0.0.0-20220706164943-b4a6d9510983
@@ -0,0 +1,122 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// batchPrealloc bounds the memory reserved up front for each batch and for the values read ahead,
+// larger batches grow as their values arrive
+const batchPrealloc = 1024
+
+type batchIterator[V any] struct {
+	src     *prefetchIterator[V]
+	size    int
+	wait    time.Duration
+	clock   Clock
+	pending error
+	done    bool
+}
+
+// Batch groups the values of i into slices which are emitted once they hold maxSize values, or
+// maxWait after the first value of the batch arrived. The source is pulled on a background
+// goroutine so a slow source such as a ChanIterator can't hold a partial batch back, closing
+// interrupts a pending pull just as for Prefetch.
+func Batch[V any](i Iterator[V], maxSize int, maxWait time.Duration) Iterator[[]V] {
+	return BatchWithClock(i, maxSize, maxWait, SystemClock)
+}
+
+func BatchWithClock[V any](i Iterator[V], maxSize int, maxWait time.Duration, clock Clock) Iterator[[]V] {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+
+	return &batchIterator[V]{
+		src: &prefetchIterator[V]{
+			in:   i,
+			size: min(maxSize, batchPrealloc),
+		},
+		size:  maxSize,
+		wait:  maxWait,
+		clock: clock,
+	}
+}
+
+func (b *batchIterator[V]) Next() ([]V, error, bool) {
+	return b.nextContext(context.Background())
+}
+
+func (b *batchIterator[V]) nextContext(ctx context.Context) ([]V, error, bool) {
+	// An error which interrupted the previous batch is reported once that batch was emitted
+	if err := b.pending; err != nil {
+		b.pending = nil
+		return nil, err, true
+	}
+	if b.done {
+		return nil, nil, false
+	}
+	if !b.src.started {
+		b.src.start()
+	}
+
+	batch := make([]V, 0, min(b.size, batchPrealloc))
+	var timeout <-chan time.Time
+	for len(batch) < b.size {
+		select {
+		case r, ok := <-b.src.buffer:
+			if !ok || !r.ok {
+				b.done = true
+				b.src.shutdown()
+				if len(batch) == 0 {
+					return nil, nil, false
+				}
+
+				return batch, nil, true
+			}
+			if r.err != nil {
+				if len(batch) == 0 {
+					return nil, r.err, true
+				}
+
+				b.pending = r.err
+				return batch, nil, true
+			}
+
+			batch = append(batch, r.v)
+			if timeout == nil {
+				timeout = b.clock.After(b.wait)
+			}
+		case <-timeout:
+			return batch, nil, true
+		case <-ctx.Done():
+			// The values gathered so far stay with the batch, ctx is reported once it is emitted
+			if len(batch) == 0 {
+				return nil, ctx.Err(), true
+			}
+
+			b.pending = ctx.Err()
+			return batch, nil, true
+		}
+	}
+
+	return batch, nil, true
+}
+
+func (b *batchIterator[V]) Reset() error {
+	if err := b.src.Reset(); err != nil {
+		return err
+	}
+
+	b.pending = nil
+	b.done = false
+	return nil
+}
+
+func (b *batchIterator[V]) Close() error {
+	return b.src.Close()
+}
+
+func (b *batchIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Batch(%d, %v)", b.size, b.wait), []any{b.src.in}
+}
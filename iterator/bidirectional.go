@@ -0,0 +1,150 @@
+package iterator
+
+// Bidirectional is implemented by iterators which can also walk backwards, Prev returns the value
+// before the current position and moves back over it.
+type Bidirectional[V any] interface {
+	Iterator[V]
+	Prev() (V, error, bool)
+}
+
+func (s *SliceIterator[V]) Prev() (V, error, bool) {
+	if s.index == 0 {
+		var v V
+		return v, nil, false
+	}
+
+	s.index--
+	return s.s[s.index], nil, true
+}
+
+func (s *StringIterator) Prev() (string, error, bool) {
+	if s.index == 0 {
+		return "", nil, false
+	}
+
+	s.index--
+	return string(s.s[s.index]), nil, true
+}
+
+func (m *MatrixIterator[V]) Prev() (V, error, bool) {
+	// Step back over finished and empty rows
+	for m.dim2index == 0 {
+		if m.dim1index == 0 {
+			var v V
+			return v, nil, false
+		}
+
+		m.dim1index--
+		m.dim2index = len(m.m[m.dim1index])
+	}
+
+	m.dim2index--
+	return m.m[m.dim1index][m.dim2index], nil, true
+}
+
+var _ Bidirectional[int] = &SliceIterator[int]{}
+var _ Bidirectional[string] = &StringIterator{}
+var _ Bidirectional[int] = &MatrixIterator[int]{}
+
+type bidirectionalMappedIterator[I, O any] struct {
+	*mappedIterator[I, O]
+	bidi Bidirectional[I]
+}
+
+func (m *bidirectionalMappedIterator[I, O]) Prev() (O, error, bool) {
+	return m.apply(m.bidi.Prev())
+}
+
+// Sources which are both seekable and bidirectional keep both capabilities when mapped
+type indexedMappedIterator[I, O any] struct {
+	*seekableMappedIterator[I, O]
+	bidi Bidirectional[I]
+}
+
+func (m *indexedMappedIterator[I, O]) Prev() (O, error, bool) {
+	return m.apply(m.bidi.Prev())
+}
+
+// reversedIterator lazily walks a bidirectional iterator backwards from its end to the position it
+// was at when first pulled from.
+type reversedIterator[V any] struct {
+	in        Bidirectional[V]
+	started   bool
+	remaining int
+}
+
+func (r *reversedIterator[V]) start() error {
+	r.started = true
+	r.remaining = 0
+
+	if s, ok := r.in.(Seeker); ok {
+		r.remaining = s.Len() - s.Position()
+		return s.Seek(s.Len())
+	}
+
+	for {
+		_, err, ok := r.in.Next()
+		if !ok {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.remaining++
+	}
+}
+
+func (r *reversedIterator[V]) Next() (V, error, bool) {
+	if !r.started {
+		if err := r.start(); err != nil {
+			var o V
+			return o, err, true
+		}
+	}
+	if r.remaining == 0 {
+		var o V
+		return o, nil, false
+	}
+
+	v, err, ok := r.in.Prev()
+	if ok {
+		r.remaining--
+	}
+
+	return v, err, ok
+}
+
+func (r *reversedIterator[V]) Prev() (V, error, bool) {
+	if !r.started {
+		var o V
+		return o, nil, false
+	}
+
+	v, err, ok := r.in.Next()
+	if ok {
+		r.remaining++
+	}
+
+	return v, err, ok
+}
+
+func (r *reversedIterator[V]) Reset() error {
+	if err := r.in.Reset(); err != nil {
+		return err
+	}
+
+	r.started = false
+	return nil
+}
+
+func (r *reversedIterator[V]) Close() error {
+	return Close[V](r.in)
+}
+
+func (r *reversedIterator[V]) SizeHint() (int, int, bool) {
+	if r.started {
+		return exactHint(r.remaining)
+	}
+
+	return SizeHint[V](r.in)
+}
@@ -0,0 +1,178 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ToChan pumps the values of i into the returned channel on a background goroutine, which owns i
+// and closes it once done. The first error from i, or ctx.Err() if ctx is done first, is sent on
+// the error channel before both channels are closed.
+func ToChan[V any](ctx context.Context, i Iterator[V], buf int) (<-chan V, <-chan error) {
+	out := make(chan V, buf)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(out)
+
+		if err := pump(ctx, i, func(v V) bool {
+			select {
+			case out <- v:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}); err != nil {
+			errs <- err
+		}
+	}()
+
+	return out, errs
+}
+
+// pump passes each value of i to send until i is exhausted, an error occurs or send fails because
+// ctx is done
+func pump[V any](ctx context.Context, i Iterator[V], send func(V) bool) error {
+	err := each(WithContext(ctx, i), func(v V) error {
+		if !send(v) {
+			return ctx.Err()
+		}
+
+		return nil
+	})
+	if cerr := Close(i); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// Backpressure decides how Broadcast treats consumers which aren't keeping up
+type Backpressure int
+
+const (
+	// Block waits for every consumer to accept a value before pulling the next one, so the
+	// slowest consumer sets the pace
+	Block Backpressure = iota
+	// DropSlow skips a value for any consumer whose buffer is full
+	DropSlow
+)
+
+// Broadcast delivers every value of i to each of n channels buffered by buf, following the
+// semantics of ToChan for errors and cancellation.
+func Broadcast[V any](ctx context.Context, i Iterator[V], n, buf int, bp Backpressure) ([]<-chan V, <-chan error) {
+	outs := make([]chan V, n)
+	recv := make([]<-chan V, n)
+	for j := range outs {
+		outs[j] = make(chan V, buf)
+		recv[j] = outs[j]
+	}
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		if err := pump(ctx, i, func(v V) bool {
+			for _, out := range outs {
+				if bp == DropSlow {
+					select {
+					case out <- v:
+					default:
+					}
+					continue
+				}
+
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return false
+				}
+			}
+
+			return true
+		}); err != nil {
+			errs <- err
+		}
+	}()
+
+	return recv, errs
+}
+
+type fanInIterator[V any] struct {
+	cases []reflect.SelectCase
+}
+
+// FanIn merges many channels into one iterator, choosing uniformly between the channels with a
+// value ready so none of them can starve the others. It ends once every channel is closed.
+func FanIn[V any](chans ...<-chan V) Iterator[V] {
+	cases := make([]reflect.SelectCase, len(chans))
+	for j, c := range chans {
+		cases[j] = reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(c),
+		}
+	}
+
+	return &fanInIterator[V]{
+		cases: cases,
+	}
+}
+
+// next receives the next value from any open channel, reporting whether done was closed first
+func (f *fanInIterator[V]) next(done <-chan struct{}) (V, bool, bool) {
+	var o V
+	for len(f.cases) > 0 {
+		cases := f.cases
+		if done != nil {
+			cases = append(cases[:len(cases):len(cases)], reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(done),
+			})
+		}
+
+		chosen, v, ok := reflect.Select(cases)
+		if chosen == len(f.cases) {
+			return o, true, true
+		}
+		if !ok {
+			// Stop selecting on closed channels
+			f.cases = append(f.cases[:chosen], f.cases[chosen+1:]...)
+			continue
+		}
+
+		// A nil received on a channel of an interface type becomes the zero V
+		x, _ := v.Interface().(V)
+		return x, false, true
+	}
+
+	return o, false, false
+}
+
+func (f *fanInIterator[V]) Next() (V, error, bool) {
+	v, _, ok := f.next(nil)
+	return v, nil, ok
+}
+
+func (f *fanInIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	v, cancelled, ok := f.next(ctx.Done())
+	if cancelled {
+		return v, ctx.Err(), true
+	}
+
+	return v, nil, ok
+}
+
+func (f *fanInIterator[V]) Reset() error {
+	return fmt.Errorf("cannot reset fan in iterator")
+}
+
+func (f *fanInIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("FanIn(%d)", len(f.cases)), nil
+}
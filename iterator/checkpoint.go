@@ -0,0 +1,322 @@
+package iterator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Checkpointer is implemented by iterators whose position can be persisted and later restored,
+// adapters include the checkpoints of their sources so a whole pipeline can be resumed at once.
+type Checkpointer interface {
+	Checkpoint() ([]byte, error)
+	Restore([]byte) error
+}
+
+func Checkpoint[V any](i Iterator[V]) ([]byte, error) {
+	c, ok := i.(Checkpointer)
+	if !ok {
+		return nil, fmt.Errorf("cannot checkpoint iterator of type: %T", i)
+	}
+
+	return c.Checkpoint()
+}
+
+func Restore[V any](i Iterator[V], b []byte) error {
+	c, ok := i.(Checkpointer)
+	if !ok {
+		return fmt.Errorf("cannot restore iterator of type: %T", i)
+	}
+
+	return c.Restore(b)
+}
+
+// adapterCheckpoint holds the state of an adapter alongside the checkpoint of its source
+type adapterCheckpoint[S any] struct {
+	State  S               `json:"state"`
+	Source json.RawMessage `json:"source"`
+}
+
+func checkpointWith[V, S any](i Iterator[V], state S) ([]byte, error) {
+	source, err := Checkpoint(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(adapterCheckpoint[S]{
+		State:  state,
+		Source: source,
+	})
+}
+
+// restoreWith restores the source of an adapter before handing back its state, so a failed
+// restore leaves the adapter untouched.
+func restoreWith[V, S any](i Iterator[V], b []byte) (S, error) {
+	var c adapterCheckpoint[S]
+	if err := json.Unmarshal(b, &c); err != nil {
+		var s S
+		return s, err
+	}
+	if err := Restore(i, c.Source); err != nil {
+		var s S
+		return s, err
+	}
+
+	return c.State, nil
+}
+
+func restoreIndex(s Seeker, b []byte) error {
+	var index int
+	if err := json.Unmarshal(b, &index); err != nil {
+		return err
+	}
+
+	return s.Seek(index)
+}
+
+func (s *SliceIterator[V]) Checkpoint() ([]byte, error) {
+	return json.Marshal(s.index)
+}
+
+func (s *SliceIterator[V]) Restore(b []byte) error {
+	return restoreIndex(s, b)
+}
+
+func (s *StringIterator) Checkpoint() ([]byte, error) {
+	return json.Marshal(s.index)
+}
+
+func (s *StringIterator) Restore(b []byte) error {
+	return restoreIndex(s, b)
+}
+
+func (m *MatrixIterator[V]) Checkpoint() ([]byte, error) {
+	return json.Marshal(m.Position())
+}
+
+func (m *MatrixIterator[V]) Restore(b []byte) error {
+	return restoreIndex(m, b)
+}
+
+// The reader must also implement io.Seeker to be restored
+func (r *ReadIterator) Checkpoint() ([]byte, error) {
+	return json.Marshal(r.offset)
+}
+
+func (r *ReadIterator) Restore(b []byte) error {
+	s, ok := r.r.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("cannot restore Reader iterator over non seekable reader: %T", r.r)
+	}
+
+	var offset int64
+	if err := json.Unmarshal(b, &offset); err != nil {
+		return err
+	}
+	if _, err := s.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	r.offset = offset
+	return nil
+}
+
+func (n *NaturalGenerator) Checkpoint() ([]byte, error) {
+	return json.Marshal(n.i)
+}
+
+func (n *NaturalGenerator) Restore(b []byte) error {
+	return json.Unmarshal(b, &n.i)
+}
+
+func (f *FibonnacciGenerator) Checkpoint() ([]byte, error) {
+	return json.Marshal([2]int{f.a, f.b})
+}
+
+func (f *FibonnacciGenerator) Restore(b []byte) error {
+	var state [2]int
+	if err := json.Unmarshal(b, &state); err != nil {
+		return err
+	}
+
+	f.a, f.b = state[0], state[1]
+	return nil
+}
+
+func (m *MaskGenerator) Checkpoint() ([]byte, error) {
+	return json.Marshal(m.index)
+}
+
+func (m *MaskGenerator) Restore(b []byte) error {
+	return json.Unmarshal(b, &m.index)
+}
+
+func (p *PrimeGenerator) Checkpoint() ([]byte, error) {
+	return json.Marshal(p.index)
+}
+
+func (p *PrimeGenerator) Restore(b []byte) error {
+	var index int
+	if err := json.Unmarshal(b, &index); err != nil {
+		return err
+	}
+	if index < 0 {
+		return fmt.Errorf("invalid prime generator index: %v", index)
+	}
+
+	// The primes up to the restored position may not have been computed yet in this process
+	for len(primes) < index {
+		p.index = len(primes)
+		p.Next()
+	}
+
+	p.index = index
+	return nil
+}
+
+func (m *mappedIterator[I, O]) Checkpoint() ([]byte, error) {
+	return Checkpoint(m.Iterator)
+}
+
+func (m *mappedIterator[I, O]) Restore(b []byte) error {
+	return Restore(m.Iterator, b)
+}
+
+func (f *filterIterator[I]) Checkpoint() ([]byte, error) {
+	return Checkpoint(f.Iterator)
+}
+
+func (f *filterIterator[I]) Restore(b []byte) error {
+	return Restore(f.Iterator, b)
+}
+
+func (l *LimitedIterator[V]) Checkpoint() ([]byte, error) {
+	return checkpointWith(l.Iterator, l.index)
+}
+
+func (l *LimitedIterator[V]) Restore(b []byte) error {
+	index, err := restoreWith[V, int](l.Iterator, b)
+	if err != nil {
+		return err
+	}
+
+	l.index = index
+	return nil
+}
+
+func (s *scanIterator[I, O]) Checkpoint() ([]byte, error) {
+	return checkpointWith(s.in, s.acc)
+}
+
+func (s *scanIterator[I, O]) Restore(b []byte) error {
+	acc, err := restoreWith[I, O](s.in, b)
+	if err != nil {
+		return err
+	}
+
+	s.acc = acc
+	return nil
+}
+
+func (c *ConsIterator[I, O]) Checkpoint() ([]byte, error) {
+	return checkpointWith(c.Iterator, c.empty)
+}
+
+func (c *ConsIterator[I, O]) Restore(b []byte) error {
+	empty, err := restoreWith[I, bool](c.Iterator, b)
+	if err != nil {
+		return err
+	}
+
+	c.empty = empty
+	return nil
+}
+
+func (d *distinctIterator[V, C]) Checkpoint() ([]byte, error) {
+	seen := make([]C, 0, len(d.seen))
+	for c := range d.seen {
+		seen = append(seen, c)
+	}
+
+	return checkpointWith(d.in, seen)
+}
+
+func (d *distinctIterator[V, C]) Restore(b []byte) error {
+	seen, err := restoreWith[V, []C](d.in, b)
+	if err != nil {
+		return err
+	}
+
+	d.seen = make(map[C]struct{}, len(seen))
+	for _, c := range seen {
+		d.seen[c] = struct{}{}
+	}
+
+	return nil
+}
+
+type duplicateState[V any] struct {
+	Index int `json:"index"`
+	Value V   `json:"value"`
+}
+
+func (d *duplicateIterator[V]) Checkpoint() ([]byte, error) {
+	return checkpointWith(d.i, duplicateState[V]{
+		Index: d.index,
+		Value: d.v,
+	})
+}
+
+func (d *duplicateIterator[V]) Restore(b []byte) error {
+	state, err := restoreWith[V, duplicateState[V]](d.i, b)
+	if err != nil {
+		return err
+	}
+
+	d.index, d.v = state.Index, state.Value
+	return nil
+}
+
+type mergeCheckpoint struct {
+	Swap   bool            `json:"swap"`
+	First  json.RawMessage `json:"first"`
+	Second json.RawMessage `json:"second"`
+}
+
+func (m *mergeIterator[V]) Checkpoint() ([]byte, error) {
+	first, err := Checkpoint(m.in1)
+	if err != nil {
+		return nil, err
+	}
+	second, err := Checkpoint(m.in2)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeCheckpoint{
+		Swap:   m.swap,
+		First:  first,
+		Second: second,
+	})
+}
+
+func (m *mergeIterator[V]) Restore(b []byte) error {
+	var c mergeCheckpoint
+	if err := json.Unmarshal(b, &c); err != nil {
+		return err
+	}
+	if err := Restore(m.in1, c.First); err != nil {
+		return err
+	}
+	if err := Restore(m.in2, c.Second); err != nil {
+		return err
+	}
+
+	m.swap = c.Swap
+	return nil
+}
+
+var _ Checkpointer = &SliceIterator[int]{}
+var _ Checkpointer = &ReadIterator{}
+var _ Checkpointer = &PrimeGenerator{}
+var _ Checkpointer = &distinctIterator[int, int]{}
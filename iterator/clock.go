@@ -0,0 +1,22 @@
+package iterator
+
+import "time"
+
+// Clock is the source of time for the time based adapters, tests can substitute a fake
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SystemClock is the Clock backed by the time package
+var SystemClock Clock = systemClock{}
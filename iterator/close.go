@@ -0,0 +1,23 @@
+package iterator
+
+import "errors"
+
+// Closer is implemented by iterators holding resources which should be released once iteration
+// stops. Adapters forward Close to their sources.
+type Closer interface {
+	Close() error
+}
+
+// Close releases the resources held by i and its sources, iterators which don't implement Closer
+// are left untouched.
+func Close[V any](i Iterator[V]) error {
+	if c, ok := i.(Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+func closeBoth[A, B any](a Iterator[A], b Iterator[B]) error {
+	return errors.Join(Close(a), Close(b))
+}
@@ -0,0 +1,185 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type concatIterator[V any] struct {
+	its   []Iterator[V]
+	index int
+}
+
+// Concat drains each of its in turn
+func Concat[V any](its ...Iterator[V]) Iterator[V] {
+	return &concatIterator[V]{
+		its: its,
+	}
+}
+
+func (c *concatIterator[V]) Next() (V, error, bool) {
+	return c.nextContext(context.Background())
+}
+
+func (c *concatIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	for c.index < len(c.its) {
+		v, err, ok := pull(ctx, c.its[c.index])
+		if ok {
+			return v, err, true
+		}
+
+		c.index++
+	}
+
+	var o V
+	return o, nil, false
+}
+
+func (c *concatIterator[V]) Reset() error {
+	for _, i := range c.its {
+		if err := i.Reset(); err != nil {
+			return err
+		}
+	}
+
+	c.index = 0
+	return nil
+}
+
+func (c *concatIterator[V]) Close() error {
+	return closeAll(c.its)
+}
+
+func (c *concatIterator[V]) SizeHint() (int, int, bool) {
+	return sumHints(c.its[c.index:])
+}
+
+func (c *concatIterator[V]) stage() (string, []any) {
+	return "Concat", sources(c.its)
+}
+
+func closeAll[V any](its []Iterator[V]) error {
+	errs := make([]error, len(its))
+	for j, i := range its {
+		errs[j] = Close(i)
+	}
+
+	return errors.Join(errs...)
+}
+
+func sumHints[V any](its []Iterator[V]) (int, int, bool) {
+	min, max, exact := 0, 0, true
+	for _, i := range its {
+		imin, imax, iexact := SizeHint(i)
+		min += imin
+		if max >= 0 && imax >= 0 {
+			max += imax
+		} else {
+			max = -1
+		}
+		exact = exact && iexact
+	}
+
+	return min, max, exact
+}
+
+func sources[V any](its []Iterator[V]) []any {
+	out := make([]any, len(its))
+	for j, i := range its {
+		out[j] = i
+	}
+
+	return out
+}
+
+// ExhaustedPolicy decides what Interleave does once one of its sources is exhausted
+type ExhaustedPolicy int
+
+const (
+	// SkipExhausted carries on interleaving the sources which still have values
+	SkipExhausted ExhaustedPolicy = iota
+	// StopOnExhausted ends the interleaving as soon as any source is exhausted
+	StopOnExhausted
+)
+
+type interleaveIterator[V any] struct {
+	its    []Iterator[V]
+	policy ExhaustedPolicy
+	active []Iterator[V]
+	pos    int
+	done   bool
+}
+
+// Interleave takes a value from each of its in turn, generalising Merge to any number of sources
+func Interleave[V any](policy ExhaustedPolicy, its ...Iterator[V]) Iterator[V] {
+	return &interleaveIterator[V]{
+		its:    its,
+		policy: policy,
+		active: append([]Iterator[V](nil), its...),
+	}
+}
+
+func (m *interleaveIterator[V]) Next() (V, error, bool) {
+	return m.nextContext(context.Background())
+}
+
+func (m *interleaveIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	var o V
+	for !m.done && len(m.active) > 0 {
+		v, err, ok := pull(ctx, m.active[m.pos])
+		if !ok {
+			if m.policy == StopOnExhausted {
+				m.done = true
+				break
+			}
+
+			// The next source shifts down into the exhausted source's place
+			m.active = append(m.active[:m.pos], m.active[m.pos+1:]...)
+			if m.pos >= len(m.active) {
+				m.pos = 0
+			}
+			continue
+		}
+
+		m.pos = (m.pos + 1) % len(m.active)
+		return v, err, true
+	}
+
+	return o, nil, false
+}
+
+func (m *interleaveIterator[V]) Reset() error {
+	for _, i := range m.its {
+		if err := i.Reset(); err != nil {
+			return err
+		}
+	}
+
+	m.active = append(m.active[:0], m.its...)
+	m.pos = 0
+	m.done = false
+	return nil
+}
+
+func (m *interleaveIterator[V]) Close() error {
+	return closeAll(m.its)
+}
+
+func (m *interleaveIterator[V]) SizeHint() (int, int, bool) {
+	if m.done {
+		return exactHint(0)
+	}
+
+	min, max, exact := sumHints(m.active)
+	if m.policy == StopOnExhausted {
+		// How many values are taken before the first source runs out isn't known
+		return 0, max, max == 0
+	}
+
+	return min, max, exact
+}
+
+func (m *interleaveIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Interleave(%d)", len(m.its)), sources(m.its)
+}
@@ -0,0 +1,76 @@
+package iterator
+
+import "context"
+
+// Sources which can block indefinitely implement this so that a pending Next can be interrupted
+type contextNexter[V any] interface {
+	nextContext(ctx context.Context) (V, error, bool)
+}
+
+// pull takes the next value of i, passing ctx down to sources which can be interrupted
+func pull[V any](ctx context.Context, i Iterator[V]) (V, error, bool) {
+	if n, ok := i.(contextNexter[V]); ok && ctx.Done() != nil {
+		return n.nextContext(ctx)
+	}
+
+	return i.Next()
+}
+
+type contextIterator[V any] struct {
+	Iterator[V]
+	ctx context.Context
+}
+
+// WithContext terminates the iterator once ctx is done, surfacing ctx.Err() as the error of the
+// next value. Adapters pass ctx down to the sources they pull from, so a pending receive on a
+// ChanIterator anywhere in the pipeline is also interrupted.
+func WithContext[V any](ctx context.Context, i Iterator[V]) Iterator[V] {
+	return &contextIterator[V]{
+		Iterator: i,
+		ctx:      ctx,
+	}
+}
+
+func (c *contextIterator[V]) Next() (V, error, bool) {
+	if err := c.ctx.Err(); err != nil {
+		var o V
+		return o, err, true
+	}
+
+	return pull(c.ctx, c.Iterator)
+}
+
+// A nested WithContext honours both its own context and the one passed down from outside it
+func (c *contextIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	if ctx.Done() == nil {
+		return c.Next()
+	}
+
+	both, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(c.ctx, cancel)()
+
+	v, err, ok := (&contextIterator[V]{Iterator: c.Iterator, ctx: both}).Next()
+	if cerr := c.ctx.Err(); cerr != nil && err != nil {
+		// Report this context's own error rather than the cancellation it caused
+		err = cerr
+	}
+
+	return v, err, ok
+}
+
+func (c *contextIterator[V]) Close() error {
+	return Close(c.Iterator)
+}
+
+func EachContext[V any](ctx context.Context, i Iterator[V], f func(V) error) error {
+	return Each(WithContext(ctx, i), f)
+}
+
+func CollectContext[V any](ctx context.Context, i Iterator[V]) ([]V, error) {
+	return Collect(WithContext(ctx, i))
+}
+
+func FoldContext[I, O any](ctx context.Context, i Iterator[I], acc O, f func(I, O) (O, error)) (O, error) {
+	return Fold(WithContext(ctx, i), acc, f)
+}
@@ -0,0 +1,281 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// stage is implemented by adapters so that a pipeline can be walked from its outermost iterator,
+// it returns a label for the adapter and the iterators it pulls from.
+type stage interface {
+	stage() (string, []any)
+}
+
+type namedIterator[V any] struct {
+	Iterator[V]
+	name   string
+	tracef func(format string, args ...any)
+}
+
+// Named labels a stage of a pipeline for Describe and Trace
+func Named[V any](name string, i Iterator[V]) Iterator[V] {
+	return &namedIterator[V]{
+		Iterator: i,
+		name:     name,
+	}
+}
+
+func (n *namedIterator[V]) Next() (V, error, bool) {
+	return n.nextContext(context.Background())
+}
+
+func (n *namedIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	v, err, ok := pull(ctx, n.Iterator)
+	if n.tracef != nil {
+		switch {
+		case !ok:
+			n.tracef("%s: done", n.name)
+		case err != nil:
+			n.tracef("%s: error: %v", n.name, err)
+		default:
+			n.tracef("%s: %v", n.name, v)
+		}
+	}
+
+	return v, err, ok
+}
+
+func (n *namedIterator[V]) Close() error {
+	return Close(n.Iterator)
+}
+
+func (n *namedIterator[V]) SizeHint() (int, int, bool) {
+	return SizeHint(n.Iterator)
+}
+
+func (n *namedIterator[V]) Checkpoint() ([]byte, error) {
+	return Checkpoint(n.Iterator)
+}
+
+func (n *namedIterator[V]) Restore(b []byte) error {
+	return Restore(n.Iterator, b)
+}
+
+func (n *namedIterator[V]) setTrace(tracef func(format string, args ...any)) {
+	n.tracef = tracef
+}
+
+// Trace logs every value and error crossing each Named stage of the pipeline ending in i through
+// tracef, log.Printf can be used directly.
+func Trace[V any](i Iterator[V], tracef func(format string, args ...any)) Iterator[V] {
+	walk(i, func(node any, _ int) {
+		if t, ok := node.(interface {
+			setTrace(func(format string, args ...any))
+		}); ok {
+			t.setTrace(tracef)
+		}
+	})
+
+	return i
+}
+
+type tapIterator[V any] struct {
+	Iterator[V]
+	f func(V, error)
+}
+
+// Tap calls f with every value and error pulled through it without altering them
+func Tap[V any](i Iterator[V], f func(V, error)) Iterator[V] {
+	return &tapIterator[V]{
+		Iterator: i,
+		f:        f,
+	}
+}
+
+func (t *tapIterator[V]) Next() (V, error, bool) {
+	return t.nextContext(context.Background())
+}
+
+func (t *tapIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	v, err, ok := pull(ctx, t.Iterator)
+	if ok {
+		t.f(v, err)
+	}
+
+	return v, err, ok
+}
+
+func (t *tapIterator[V]) Close() error {
+	return Close(t.Iterator)
+}
+
+func (t *tapIterator[V]) SizeHint() (int, int, bool) {
+	return SizeHint(t.Iterator)
+}
+
+// walk visits every iterator in the pipeline depth first, shared sources are visited once per use
+func walk(node any, visit func(node any, depth int)) {
+	var rec func(node any, depth int)
+	rec = func(node any, depth int) {
+		visit(node, depth)
+		if s, ok := node.(stage); ok {
+			_, sources := s.stage()
+			for _, source := range sources {
+				rec(source, depth+1)
+			}
+		}
+	}
+
+	rec(node, 0)
+}
+
+func label(node any) string {
+	if s, ok := node.(stage); ok {
+		l, _ := s.stage()
+		return l
+	}
+
+	return strings.TrimPrefix(strings.TrimPrefix(fmt.Sprintf("%T", node), "*"), "iterator.")
+}
+
+// Describe renders the pipeline ending in i as an indented tree, one stage per line
+func Describe[V any](i Iterator[V]) string {
+	var b strings.Builder
+	walk(i, func(node any, depth int) {
+		fmt.Fprintf(&b, "%s%s\n", strings.Repeat("  ", depth), label(node))
+	})
+
+	return b.String()
+}
+
+// DescribeDOT renders the pipeline ending in i as a Graphviz digraph, with edges following the
+// flow of values. Sources shared between stages, such as by Partition, appear as a single node.
+func DescribeDOT[V any](i Iterator[V]) string {
+	var b strings.Builder
+	b.WriteString("digraph pipeline {\n")
+
+	ids := make(map[any]int)
+	next := 0
+	id := func(node any) (int, bool) {
+		// Iterators which can't be used as a key, such as generating functions, are never shared
+		if !reflect.ValueOf(node).Comparable() {
+			next++
+			return next, false
+		}
+		if n, ok := ids[node]; ok {
+			return n, true
+		}
+
+		next++
+		ids[node] = next
+		return next, false
+	}
+
+	var rec func(node any) int
+	rec = func(node any) int {
+		n, seen := id(node)
+		if seen {
+			return n
+		}
+
+		fmt.Fprintf(&b, "\tn%d [label=%q];\n", n, label(node))
+		if s, ok := node.(stage); ok {
+			_, sources := s.stage()
+			for _, source := range sources {
+				fmt.Fprintf(&b, "\tn%d -> n%d;\n", rec(source), n)
+			}
+		}
+
+		return n
+	}
+
+	rec(i)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (n *namedIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Named(%s)", n.name), []any{n.Iterator}
+}
+
+func (t *tapIterator[V]) stage() (string, []any) {
+	return "Tap", []any{t.Iterator}
+}
+
+func (m *mappedIterator[I, O]) stage() (string, []any) {
+	return "Map", []any{m.Iterator}
+}
+
+func (f *filterIterator[I]) stage() (string, []any) {
+	return "Filter", []any{f.Iterator}
+}
+
+func (l *LimitedIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("WithLimit(%d)", l.limit), []any{l.Iterator}
+}
+
+func (c *ConsIterator[I, O]) stage() (string, []any) {
+	return fmt.Sprintf("Cons(%d)", c.stride), []any{c.Iterator}
+}
+
+func (s *scanIterator[I, O]) stage() (string, []any) {
+	return "Scan", []any{s.in}
+}
+
+func (d *distinctIterator[V, C]) stage() (string, []any) {
+	return "Distinct", []any{d.in}
+}
+
+func (m *mergeMapIterator[A, B, O]) stage() (string, []any) {
+	return "MergeMap", []any{m.in1, m.in2}
+}
+
+func (m *mergeIterator[V]) stage() (string, []any) {
+	return "Merge", []any{m.in1, m.in2}
+}
+
+func (s *split[V]) stage() (string, []any) {
+	return "Partition", []any{s.i}
+}
+
+func (d *duplicateIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Duplicate(%d)", d.count), []any{d.i}
+}
+
+func (s *sortedIterator[V]) stage() (string, []any) {
+	return "Sort", []any{s.in}
+}
+
+func (b *bindIterator[I, O]) stage() (string, []any) {
+	if b.curr == nil {
+		return "Bind", []any{b.in}
+	}
+
+	return "Bind", []any{b.in, b.curr}
+}
+
+func (i *clonedIterator[V]) stage() (string, []any) {
+	return "Clone", []any{i.base}
+}
+
+func (r *reversedIterator[V]) stage() (string, []any) {
+	return "Reverse", []any{r.in}
+}
+
+func (c *contextIterator[V]) stage() (string, []any) {
+	return "WithContext", []any{c.Iterator}
+}
+
+func (p *PeekableIterator[V]) stage() (string, []any) {
+	return "Peekable", []any{p.in}
+}
+
+func (s *skipErrorsIterator[V]) stage() (string, []any) {
+	return "SkipErrors", []any{s.Iterator}
+}
+
+func (s *stopAfterErrorsIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("StopAfterNErrors(%d)", s.limit), []any{s.Iterator}
+}
@@ -0,0 +1,142 @@
+package iterator
+
+import (
+	"context"
+
+	"github.com/lucas-s-work/funcy-go/queue"
+	"github.com/lucas-s-work/funcy-go/tuple"
+)
+
+// fork shares a source between a left and a right iterator. Each value pulled is split into an
+// optional value for either side, whatever belongs to the other side is buffered in the style of
+// Partition until that side pulls it.
+type fork[V, L, R any] struct {
+	in          Iterator[V]
+	split       func(V, error) (tuple.Option[L], tuple.Option[R], error)
+	left        queue.Queue[L]
+	right       queue.Queue[R]
+	leftClosed  bool
+	rightClosed bool
+	leftLabel   string
+	rightLabel  string
+}
+
+func newFork[V, L, R any](i Iterator[V], split func(V, error) (tuple.Option[L], tuple.Option[R], error), leftLabel, rightLabel string) (Iterator[L], Iterator[R]) {
+	f := &fork[V, L, R]{
+		in:         i,
+		split:      split,
+		leftLabel:  leftLabel,
+		rightLabel: rightLabel,
+	}
+
+	return &forkLeft[V, L, R]{f}, &forkRight[V, L, R]{f}
+}
+
+func (f *fork[V, L, R]) pull(ctx context.Context) (tuple.Option[L], tuple.Option[R], error, bool) {
+	v, err, ok := pull(ctx, f.in)
+	if !ok {
+		return tuple.None[L](), tuple.None[R](), nil, false
+	}
+	// Cancellation is reported to the side pulling rather than being split off like a failed value
+	if cerr := ctx.Err(); err != nil && cerr != nil {
+		return tuple.None[L](), tuple.None[R](), cerr, true
+	}
+
+	l, r, err := f.split(v, err)
+	return l, r, err, true
+}
+
+func (f *fork[V, L, R]) Reset() error {
+	if err := f.in.Reset(); err != nil {
+		return err
+	}
+
+	f.left = queue.Queue[L]{}
+	f.right = queue.Queue[R]{}
+	return nil
+}
+
+// The shared source is only closed once both sides are closed
+func (f *fork[V, L, R]) close() error {
+	if !f.leftClosed || !f.rightClosed {
+		return nil
+	}
+
+	return Close(f.in)
+}
+
+type forkLeft[V, L, R any] struct {
+	*fork[V, L, R]
+}
+
+func (f *forkLeft[V, L, R]) Next() (L, error, bool) {
+	return f.nextContext(context.Background())
+}
+
+func (f *forkLeft[V, L, R]) nextContext(ctx context.Context) (L, error, bool) {
+	if v, ok := f.left.Pop(); ok {
+		return v, nil, true
+	}
+
+	for {
+		l, r, err, ok := f.pull(ctx)
+		if !ok || err != nil {
+			var o L
+			return o, err, ok
+		}
+
+		if r.Ok {
+			f.right.Push(r.Value)
+		}
+		if l.Ok {
+			return l.Value, nil, true
+		}
+	}
+}
+
+func (f *forkLeft[V, L, R]) Close() error {
+	f.leftClosed = true
+	return f.close()
+}
+
+func (f *forkLeft[V, L, R]) stage() (string, []any) {
+	return f.leftLabel, []any{f.in}
+}
+
+type forkRight[V, L, R any] struct {
+	*fork[V, L, R]
+}
+
+func (f *forkRight[V, L, R]) Next() (R, error, bool) {
+	return f.nextContext(context.Background())
+}
+
+func (f *forkRight[V, L, R]) nextContext(ctx context.Context) (R, error, bool) {
+	if v, ok := f.right.Pop(); ok {
+		return v, nil, true
+	}
+
+	for {
+		l, r, err, ok := f.pull(ctx)
+		if !ok || err != nil {
+			var o R
+			return o, err, ok
+		}
+
+		if l.Ok {
+			f.left.Push(l.Value)
+		}
+		if r.Ok {
+			return r.Value, nil, true
+		}
+	}
+}
+
+func (f *forkRight[V, L, R]) Close() error {
+	f.rightClosed = true
+	return f.close()
+}
+
+func (f *forkRight[V, L, R]) stage() (string, []any) {
+	return f.rightLabel, []any{f.in}
+}
@@ -0,0 +1,42 @@
+package iterator
+
+// SizeHinter is implemented by iterators which know bounds on the number of values they have left
+// to produce. A max of -1 means there is no known upper bound, exact means min == max.
+type SizeHinter interface {
+	SizeHint() (min, max int, exact bool)
+}
+
+// SizeHint returns the remaining size bounds of i, or (0, -1, false) if they aren't known
+func SizeHint[V any](i Iterator[V]) (min, max int, exact bool) {
+	if h, ok := i.(SizeHinter); ok {
+		return h.SizeHint()
+	}
+
+	return 0, -1, false
+}
+
+// capacityHint gives the number of values worth preallocating for when draining i
+func capacityHint[V any](i Iterator[V]) int {
+	min, max, exact := SizeHint(i)
+	if exact {
+		return max
+	}
+
+	return min
+}
+
+func exactHint(n int) (int, int, bool) {
+	if n < 0 {
+		n = 0
+	}
+
+	return n, n, true
+}
+
+func ceilDiv(n, d int) int {
+	if n < 0 {
+		return n
+	}
+
+	return (n + d - 1) / d
+}
@@ -1,6 +1,7 @@
 package iterator
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -40,6 +41,10 @@ func (s *SliceIterator[V]) Reset() error {
 	return nil
 }
 
+func (s *SliceIterator[V]) SizeHint() (int, int, bool) {
+	return exactHint(len(s.s) - s.index)
+}
+
 var _ Iterator[int] = &SliceIterator[int]{}
 
 type MapIterator[K constraints.Ordered, V any, O KeyValue[K, V]] struct {
@@ -56,8 +61,9 @@ type KeyValue[K constraints.Ordered, V any] struct {
 func NewMapIterator[K constraints.Ordered, V any](m map[K]V) Iterator[KeyValue[K, V]] {
 	keys := make([]K, len(m))
 	i := 0
-	for k, _ := range m {
+	for k := range m {
 		keys[i] = k
+		i++
 	}
 
 	return &MapIterator[K, V, KeyValue[K, V]]{
@@ -68,7 +74,7 @@ func NewMapIterator[K constraints.Ordered, V any](m map[K]V) Iterator[KeyValue[K
 }
 
 func (m *MapIterator[K, V, O]) Next() (O, error, bool) {
-	if m.keyIndex > len(m.keys) {
+	if m.keyIndex >= len(m.keys) {
 		var o O
 		return o, nil, false
 	}
@@ -88,7 +94,11 @@ func (s *MapIterator[K, V, O]) Reset() error {
 	return nil
 }
 
-var _ Iterator[KeyValue[int, int]] = &MapIterator[int, int]{}
+func (m *MapIterator[K, V, O]) SizeHint() (int, int, bool) {
+	return exactHint(len(m.keys) - m.keyIndex)
+}
+
+var _ Iterator[KeyValue[int, int]] = &MapIterator[int, int, KeyValue[int, int]]{}
 
 type ChanIterator[V any] struct {
 	c    chan V
@@ -107,6 +117,16 @@ func (c *ChanIterator[V]) Next() (V, error, bool) {
 	return v, nil, ok
 }
 
+func (c *ChanIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	select {
+	case v, ok := <-c.c:
+		return v, nil, ok
+	case <-ctx.Done():
+		var o V
+		return o, ctx.Err(), true
+	}
+}
+
 func (s *ChanIterator[V]) Reset() error {
 	var v V
 	return fmt.Errorf("cannot reset channel iterator for type: %T", v)
@@ -129,7 +149,8 @@ func NewMatrixIterator[V any](m [][]V) Iterator[V] {
 }
 
 func (m *MatrixIterator[V]) Next() (V, error, bool) {
-	if m.dim2index >= len(m.m[m.dim1index]) {
+	// Skip over finished and empty rows
+	for m.dim1index < len(m.m) && m.dim2index >= len(m.m[m.dim1index]) {
 		m.dim2index = 0
 		m.dim1index++
 	}
@@ -140,7 +161,7 @@ func (m *MatrixIterator[V]) Next() (V, error, bool) {
 	}
 
 	m.dim2index++
-	return m.m[m.dim1index][m.dim2index], nil, true
+	return m.m[m.dim1index][m.dim2index-1], nil, true
 }
 
 func (s *MatrixIterator[V]) Reset() error {
@@ -149,6 +170,18 @@ func (s *MatrixIterator[V]) Reset() error {
 	return nil
 }
 
+func (m *MatrixIterator[V]) SizeHint() (int, int, bool) {
+	remaining := 0
+	for i := m.dim1index; i < len(m.m); i++ {
+		remaining += len(m.m[i])
+	}
+	if m.dim1index < len(m.m) {
+		remaining -= m.dim2index
+	}
+
+	return exactHint(remaining)
+}
+
 var _ Iterator[int] = &MatrixIterator[int]{}
 
 type StringIterator struct {
@@ -177,6 +210,10 @@ func (s *StringIterator) Reset() error {
 	return nil
 }
 
+func (s *StringIterator) SizeHint() (int, int, bool) {
+	return exactHint(len(s.s) - s.index)
+}
+
 var _ Iterator[int] = &SliceIterator[int]{}
 
 type LimitedIterator[V any] struct {
@@ -186,20 +223,32 @@ type LimitedIterator[V any] struct {
 }
 
 func WithLimit[V any](i Iterator[V], limit int) Iterator[V] {
-	return &LimitedIterator[V]{
+	l := &LimitedIterator[V]{
 		Iterator: i,
 		limit:    limit,
 		index:    0,
 	}
+	if s, ok := i.(Seeker); ok {
+		return &seekableLimitedIterator[V]{
+			LimitedIterator: l,
+			seeker:          s,
+		}
+	}
+
+	return l
 }
 
 func (l *LimitedIterator[V]) Next() (V, error, bool) {
+	return l.nextContext(context.Background())
+}
+
+func (l *LimitedIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
 	if l.index == l.limit {
 		var o V
 		return o, nil, false
 	}
 
-	v, err, ok := l.Iterator.Next()
+	v, err, ok := pull(ctx, l.Iterator)
 	if !ok {
 		return v, err, false
 	}
@@ -220,9 +269,27 @@ func (l *LimitedIterator[V]) Reset() error {
 	return nil
 }
 
+func (l *LimitedIterator[V]) Close() error {
+	return Close(l.Iterator)
+}
+
+func (l *LimitedIterator[V]) SizeHint() (int, int, bool) {
+	remaining := l.limit - l.index
+	min, max, exact := SizeHint(l.Iterator)
+	if min >= remaining {
+		return exactHint(remaining)
+	}
+	if max < 0 || max > remaining {
+		return min, remaining, false
+	}
+
+	return min, max, exact
+}
+
 type ReadIterator struct {
 	r      io.Reader
 	values []byte
+	offset int64
 }
 
 func NewReaderIterator(r io.Reader, stride int) Iterator[[]byte] {
@@ -234,6 +301,7 @@ func NewReaderIterator(r io.Reader, stride int) Iterator[[]byte] {
 
 func (r *ReadIterator) Next() ([]byte, error, bool) {
 	read, err := r.r.Read(r.values)
+	r.offset += int64(read)
 	if err != nil {
 		return nil, err, true
 	}
@@ -247,3 +315,12 @@ func (r *ReadIterator) Next() ([]byte, error, bool) {
 func (r *ReadIterator) Reset() error {
 	return fmt.Errorf("cannot reset Reader iterator")
 }
+
+// Close closes the underlying reader if it is an io.Closer
+func (r *ReadIterator) Close() error {
+	if c, ok := r.r.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package iterator
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+type sortedHead[V any] struct {
+	v      V
+	source int
+}
+
+// mergeHeap holds the next value of each source, ties are broken by source order so the merge is
+// stable
+type mergeHeap[V any] struct {
+	heads []sortedHead[V]
+	less  func(a, b V) bool
+}
+
+func (h *mergeHeap[V]) Len() int {
+	return len(h.heads)
+}
+
+func (h *mergeHeap[V]) Less(i, j int) bool {
+	a, b := h.heads[i], h.heads[j]
+	if h.less(a.v, b.v) {
+		return true
+	}
+	if h.less(b.v, a.v) {
+		return false
+	}
+
+	return a.source < b.source
+}
+
+func (h *mergeHeap[V]) Swap(i, j int) {
+	h.heads[i], h.heads[j] = h.heads[j], h.heads[i]
+}
+
+func (h *mergeHeap[V]) Push(x any) {
+	h.heads = append(h.heads, x.(sortedHead[V]))
+}
+
+func (h *mergeHeap[V]) Pop() any {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
+
+type mergeSortedIterator[V any] struct {
+	its    []Iterator[V]
+	unique bool
+	heap   *mergeHeap[V]
+	// Sources whose next value still needs pulling onto the heap
+	unprimed []int
+	last     V
+	emitted  bool
+}
+
+func newMergeSorted[V any](less func(a, b V) bool, unique bool, its []Iterator[V]) *mergeSortedIterator[V] {
+	m := &mergeSortedIterator[V]{
+		its:    its,
+		unique: unique,
+		heap: &mergeHeap[V]{
+			less: less,
+		},
+	}
+	m.clear()
+
+	return m
+}
+
+// MergeSorted lazily merges iterators which are each sorted by less into a single sorted iterator,
+// taking O(log n) per value for n sources.
+func MergeSorted[V any](less func(a, b V) bool, its ...Iterator[V]) Iterator[V] {
+	return newMergeSorted(less, false, its)
+}
+
+// MergeSortedUnique merges like MergeSorted while dropping values equal to the previous one, so
+// duplicates within and across sources are removed.
+func MergeSortedUnique[V any](less func(a, b V) bool, its ...Iterator[V]) Iterator[V] {
+	return newMergeSorted(less, true, its)
+}
+
+func MergeSortedOrdered[V constraints.Ordered](its ...Iterator[V]) Iterator[V] {
+	return MergeSorted(func(a, b V) bool { return a < b }, its...)
+}
+
+func (m *mergeSortedIterator[V]) clear() {
+	m.heap.heads = m.heap.heads[:0]
+	m.unprimed = m.unprimed[:0]
+	for j := range m.its {
+		m.unprimed = append(m.unprimed, j)
+	}
+	m.emitted = false
+}
+
+// prime pulls the next value of every source which was last taken from, a source which errors is
+// pulled again on the next call
+func (m *mergeSortedIterator[V]) prime(ctx context.Context) error {
+	for len(m.unprimed) > 0 {
+		source := m.unprimed[len(m.unprimed)-1]
+		v, err, ok := pull(ctx, m.its[source])
+		if ok && err != nil {
+			return err
+		}
+
+		m.unprimed = m.unprimed[:len(m.unprimed)-1]
+		if ok {
+			heap.Push(m.heap, sortedHead[V]{v: v, source: source})
+		}
+	}
+
+	return nil
+}
+
+func (m *mergeSortedIterator[V]) Next() (V, error, bool) {
+	return m.nextContext(context.Background())
+}
+
+func (m *mergeSortedIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	var o V
+	for {
+		if err := m.prime(ctx); err != nil {
+			return o, err, true
+		}
+		if m.heap.Len() == 0 {
+			return o, nil, false
+		}
+
+		head := heap.Pop(m.heap).(sortedHead[V])
+		m.unprimed = append(m.unprimed, head.source)
+		if m.unique && m.emitted && !m.heap.less(m.last, head.v) {
+			continue
+		}
+
+		m.last = head.v
+		m.emitted = true
+		return head.v, nil, true
+	}
+}
+
+func (m *mergeSortedIterator[V]) Reset() error {
+	for _, i := range m.its {
+		if err := i.Reset(); err != nil {
+			return err
+		}
+	}
+
+	m.clear()
+	return nil
+}
+
+func (m *mergeSortedIterator[V]) Close() error {
+	return closeAll(m.its)
+}
+
+func (m *mergeSortedIterator[V]) SizeHint() (int, int, bool) {
+	min, max, exact := sumHints(m.its)
+	min += m.heap.Len()
+	if max >= 0 {
+		max += m.heap.Len()
+	}
+	if m.unique {
+		return 0, max, max == 0
+	}
+
+	return min, max, exact
+}
+
+func (m *mergeSortedIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("MergeSorted(%d)", len(m.its)), sources(m.its)
+}
@@ -0,0 +1,182 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Observer receives measurements from the stages of an instrumented pipeline, it must be safe to
+// call from multiple goroutines if the pipeline is shared between them.
+type Observer interface {
+	// ObserveElement is called for every value a stage produces along with the time taken to
+	// produce it, including the time spent pulling from its sources.
+	ObserveElement(stage string, latency time.Duration)
+	ObserveError(stage string, err error)
+	// ObserveDrop is called for every value a stage discards, such as those rejected by Filter
+	ObserveDrop(stage string)
+}
+
+type observer struct {
+	Observer
+	stage string
+}
+
+func observeNext[V any](o *observer, next func() (V, error, bool)) (V, error, bool) {
+	start := time.Now()
+	v, err, ok := next()
+	if !ok {
+		return v, err, ok
+	}
+
+	if err != nil {
+		o.ObserveError(o.stage, err)
+	} else {
+		o.ObserveElement(o.stage, time.Since(start))
+	}
+
+	return v, err, ok
+}
+
+func observeFold[I, O any](obs Observer, f func(I, O) (O, error)) func(I, O) (O, error) {
+	o := &observer{
+		Observer: obs,
+		stage:    "Fold",
+	}
+
+	return func(v I, acc O) (O, error) {
+		start := time.Now()
+		acc, err := f(v, acc)
+		if err != nil {
+			o.ObserveError(o.stage, err)
+		} else {
+			o.ObserveElement(o.stage, time.Since(start))
+		}
+
+		return acc, err
+	}
+}
+
+// Implemented by the adapters which report to an Observer
+type observable interface {
+	observe(o *observer)
+}
+
+func (m *mappedIterator[I, O]) observe(o *observer) {
+	m.obs = o
+}
+
+func (f *filterIterator[I]) observe(o *observer) {
+	f.obs = o
+}
+
+func (b *bindIterator[I, O]) observe(o *observer) {
+	b.obs = o
+}
+
+func (s *split[V]) observe(o *observer) {
+	s.obs = o
+}
+
+func (n *namedIterator[V]) named() string {
+	return n.name
+}
+
+// Implemented by pipelines wrapped by Instrument, wrappers such as WithContext forward it so a Fold
+// over them is still reported
+type instrumented interface {
+	foldObserver() Observer
+}
+
+func foldObserver(i any) Observer {
+	if in, ok := i.(instrumented); ok {
+		return in.foldObserver()
+	}
+
+	return nil
+}
+
+func (i *instrumentedIterator[V]) foldObserver() Observer {
+	return i.obs
+}
+
+func (c *contextIterator[V]) foldObserver() Observer {
+	return foldObserver(c.Iterator)
+}
+
+func (n *namedIterator[V]) foldObserver() Observer {
+	return foldObserver(n.Iterator)
+}
+
+type instrumentedIterator[V any] struct {
+	Iterator[V]
+	obs Observer
+}
+
+// Instrument attaches obs to every Map, Filter, Bind and Partition stage of the pipeline ending in
+// i, and to Fold when it consumes the returned iterator. Stages are reported under the name given
+// by a directly enclosing Named, otherwise under their kind numbered in the order they are found.
+func Instrument[V any](obs Observer, i Iterator[V]) Iterator[V] {
+	counts := make(map[string]int)
+
+	var rec func(node any, name string)
+	rec = func(node any, name string) {
+		if n, ok := node.(interface{ named() string }); ok {
+			name = n.named()
+		} else if o, ok := node.(observable); ok {
+			if name == "" {
+				l := label(node)
+				counts[l]++
+				name = l
+				if counts[l] > 1 {
+					name = fmt.Sprintf("%s#%d", l, counts[l])
+				}
+			}
+
+			o.observe(&observer{
+				Observer: obs,
+				stage:    name,
+			})
+			name = ""
+		} else {
+			name = ""
+		}
+
+		if s, ok := node.(stage); ok {
+			_, sources := s.stage()
+			for _, source := range sources {
+				rec(source, name)
+			}
+		}
+	}
+
+	rec(i, "")
+	return &instrumentedIterator[V]{
+		Iterator: i,
+		obs:      obs,
+	}
+}
+
+func (i *instrumentedIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	return pull(ctx, i.Iterator)
+}
+
+func (i *instrumentedIterator[V]) Close() error {
+	return Close(i.Iterator)
+}
+
+func (i *instrumentedIterator[V]) SizeHint() (int, int, bool) {
+	return SizeHint(i.Iterator)
+}
+
+func (i *instrumentedIterator[V]) Checkpoint() ([]byte, error) {
+	return Checkpoint(i.Iterator)
+}
+
+func (i *instrumentedIterator[V]) Restore(b []byte) error {
+	return Restore(i.Iterator, b)
+}
+
+func (i *instrumentedIterator[V]) stage() (string, []any) {
+	return "Instrument", []any{i.Iterator}
+}
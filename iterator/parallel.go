@@ -0,0 +1,221 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type result[V any] struct {
+	v   V
+	err error
+	ok  bool
+}
+
+// protect calls f, reporting a panic as an error so a single bad value can't crash the process
+func protect(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("worker panic: %v", r)
+		}
+	}()
+
+	return f()
+}
+
+// workerPool runs the goroutines behind the parallel adapters, which start them on the first pull.
+// ctx is cancelled on shutdown, feeders pull through it so a blocked source is interrupted.
+type workerPool struct {
+	started  bool
+	done     bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	finished chan struct{}
+}
+
+// start runs n copies of work alongside feed, which is the only goroutine to pull from the source.
+// Once they have all returned after is called, then shutdown is released.
+func (w *workerPool) start(n int, feed, work, after func()) {
+	w.started = true
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.finished = make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(n + 1)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			work()
+		}()
+	}
+	go func() {
+		defer wg.Done()
+		feed()
+	}()
+
+	go func() {
+		wg.Wait()
+		after()
+		close(w.finished)
+	}()
+}
+
+// shutdown stops the workers and waits for them to exit, after which nothing else touches the
+// source. A pending pull is interrupted if the source supports cancellation, otherwise it is
+// waited for.
+func (w *workerPool) shutdown() {
+	w.done = true
+	if !w.started {
+		return
+	}
+
+	w.cancel()
+	<-w.finished
+	w.started = false
+}
+
+type parallelMapIterator[I, O any] struct {
+	workerPool
+	in      Iterator[I]
+	workers int
+	f       func(I) (O, error)
+	pending chan chan result[O]
+	// slot is taken from pending but not yet received from, a cancelled wait resumes from it
+	slot chan result[O]
+}
+
+// ParallelMap applies f to the values of i on a pool of workers goroutines, producing the results
+// in the same order as the input. At most twice as many values as workers are in flight at once,
+// the first error stops the pipeline. Close should be called if the results aren't drained so
+// the workers can be shut down, Each, Collect and First do this automatically. Closing interrupts
+// a pending pull from i if it supports cancellation, such as a ChanIterator, otherwise it waits
+// for the pull to return.
+func ParallelMap[I, O any](i Iterator[I], workers int, f func(I) (O, error)) Iterator[O] {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &parallelMapIterator[I, O]{
+		in:      i,
+		workers: workers,
+		f:       f,
+	}
+}
+
+func (p *parallelMapIterator[I, O]) start() {
+	p.pending = make(chan chan result[O], 2*p.workers)
+	p.slot = nil
+	jobs := make(chan func(), p.workers)
+
+	work := func() {
+		for job := range jobs {
+			job()
+		}
+	}
+
+	// Each value is given a slot in pending so the consumer can wait on results in order
+	// regardless of which worker finishes first
+	feed := func() {
+		defer close(jobs)
+		defer close(p.pending)
+
+		for {
+			v, err, ok := pull(p.ctx, p.in)
+			out := make(chan result[O], 1)
+			if !ok || err != nil {
+				out <- result[O]{err: err, ok: ok}
+			}
+
+			select {
+			case p.pending <- out:
+			case <-p.ctx.Done():
+				return
+			}
+			if !ok || err != nil {
+				return
+			}
+
+			select {
+			case jobs <- func() {
+				var o O
+				err := protect(func() (err error) {
+					o, err = p.f(v)
+					return err
+				})
+				out <- result[O]{v: o, err: err, ok: true}
+			}:
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}
+
+	p.workerPool.start(p.workers, feed, work, func() {})
+}
+
+func (p *parallelMapIterator[I, O]) Next() (O, error, bool) {
+	return p.nextContext(context.Background())
+}
+
+func (p *parallelMapIterator[I, O]) nextContext(ctx context.Context) (O, error, bool) {
+	var o O
+	if p.done {
+		return o, nil, false
+	}
+	if !p.started {
+		p.start()
+	}
+
+	if p.slot == nil {
+		select {
+		case out, ok := <-p.pending:
+			if !ok {
+				p.shutdown()
+				return o, nil, false
+			}
+			p.slot = out
+		case <-ctx.Done():
+			return o, ctx.Err(), true
+		}
+	}
+
+	var r result[O]
+	select {
+	case r = <-p.slot:
+		p.slot = nil
+	case <-ctx.Done():
+		return o, ctx.Err(), true
+	}
+	if !r.ok || r.err != nil {
+		p.shutdown()
+	}
+
+	return r.v, r.err, r.ok
+}
+
+func (p *parallelMapIterator[I, O]) Reset() error {
+	p.shutdown()
+	if err := p.in.Reset(); err != nil {
+		return err
+	}
+
+	p.done = false
+	return nil
+}
+
+func (p *parallelMapIterator[I, O]) Close() error {
+	p.shutdown()
+	return Close(p.in)
+}
+
+func (p *parallelMapIterator[I, O]) SizeHint() (int, int, bool) {
+	if p.started || p.done {
+		return 0, -1, false
+	}
+
+	return SizeHint(p.in)
+}
+
+func (p *parallelMapIterator[I, O]) stage() (string, []any) {
+	return fmt.Sprintf("ParallelMap(%d)", p.workers), []any{p.in}
+}
@@ -0,0 +1,117 @@
+package iterator
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ParallelFold folds the values of i on workers goroutines, each starting from a fresh accumulator
+// returned by zero and pulling values from i as it is ready for them. The partial accumulators are
+// then merged with combine in worker order. Since values are spread between workers unpredictably,
+// combine must be associative and step must not depend on the order values arrive in. The first
+// error stops every worker.
+func ParallelFold[I, O any](i Iterator[I], workers int, zero func() O, step func(I, O) (O, error), combine func(O, O) (O, error)) (O, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	next := WorkSource(i)
+	partials := make([]O, workers)
+	errs := make([]error, workers)
+
+	var failed sync.Once
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			acc := zero()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				v, err, ok := next()
+				if !ok {
+					break
+				}
+				if err == nil {
+					err = protect(func() (err error) {
+						acc, err = step(v, acc)
+						return err
+					})
+				}
+				if err != nil {
+					errs[w] = err
+					failed.Do(func() { close(stop) })
+					return
+				}
+			}
+
+			partials[w] = acc
+		}()
+	}
+	wg.Wait()
+
+	if cerr := Close(i); cerr != nil {
+		errs = append(errs, cerr)
+	}
+	if err := errors.Join(errs...); err != nil {
+		var o O
+		return o, err
+	}
+
+	acc := partials[0]
+	for _, p := range partials[1:] {
+		var err error
+		if acc, err = combine(acc, p); err != nil {
+			var o O
+			return o, err
+		}
+	}
+
+	return acc, nil
+}
+
+// ParallelSum only accepts numeric types, summing strings would depend on the order values arrive in
+func ParallelSum[V Multable](i Iterator[V], workers int) (V, error) {
+	add := func(v, acc V) (V, error) { return acc + v, nil }
+	return ParallelFold(i, workers, func() V { var zero V; return zero }, add, add)
+}
+
+func ParallelCount[V any](i Iterator[V], workers int) (int, error) {
+	return ParallelCountFilter(i, workers, func(V) bool { return true })
+}
+
+func ParallelCountFilter[V any](i Iterator[V], workers int, check func(V) bool) (int, error) {
+	return ParallelFold(i, workers, func() int { return 0 }, func(v V, count int) (int, error) {
+		if check(v) {
+			return count + 1, nil
+		}
+
+		return count, nil
+	}, func(a, b int) (int, error) { return a + b, nil })
+}
+
+// ParallelGroupBy groups the values of i by key on workers goroutines, the order of the values
+// within each group is not preserved.
+func ParallelGroupBy[K constraints.Ordered, V any](i Iterator[V], workers int, f func(V) K) (map[K][]V, error) {
+	return ParallelFold(i, workers, func() map[K][]V { return make(map[K][]V) }, func(v V, m map[K][]V) (map[K][]V, error) {
+		k := f(v)
+		m[k] = append(m[k], v)
+		return m, nil
+	}, func(a, b map[K][]V) (map[K][]V, error) {
+		for k, vs := range b {
+			a[k] = append(a[k], vs...)
+		}
+
+		return a, nil
+	})
+}
@@ -0,0 +1,106 @@
+package iterator
+
+import (
+	"context"
+
+	"github.com/lucas-s-work/funcy-go/queue"
+)
+
+// PeekableIterator provides lookahead and push-back over another iterator
+type PeekableIterator[V any] struct {
+	in     Iterator[V]
+	buffer queue.Queue[V]
+	err    error
+}
+
+func Peekable[V any](i Iterator[V]) *PeekableIterator[V] {
+	return &PeekableIterator[V]{
+		in:     i,
+		buffer: queue.Queue[V]{},
+	}
+}
+
+// fill buffers values until n are available, returning false if the source is exhausted first.
+// An error from the source is held back until the buffered values before it have been consumed.
+func (p *PeekableIterator[V]) fill(n int) (error, bool) {
+	for p.buffer.Len() < n {
+		if p.err != nil {
+			return p.err, true
+		}
+
+		v, err, ok := p.in.Next()
+		if !ok {
+			return nil, false
+		}
+		if err != nil {
+			p.err = err
+			return err, true
+		}
+
+		p.buffer.Push(v)
+	}
+
+	return nil, true
+}
+
+func (p *PeekableIterator[V]) Next() (V, error, bool) {
+	return p.nextContext(context.Background())
+}
+
+func (p *PeekableIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	if v, ok := p.buffer.Pop(); ok {
+		return v, nil, true
+	}
+	if err := p.err; err != nil {
+		p.err = nil
+
+		var o V
+		return o, err, true
+	}
+
+	return pull(ctx, p.in)
+}
+
+// Peek returns the next value without consuming it
+func (p *PeekableIterator[V]) Peek() (V, error, bool) {
+	var o V
+	err, ok := p.fill(1)
+	if !ok {
+		return o, nil, false
+	}
+	if err != nil {
+		return o, err, true
+	}
+
+	v, _ := p.buffer.Peek()
+	return v, nil, true
+}
+
+// PeekN returns up to the next n values without consuming them, fewer are returned if the source
+// is exhausted.
+func (p *PeekableIterator[V]) PeekN(n int) ([]V, error) {
+	if err, _ := p.fill(n); err != nil {
+		return nil, err
+	}
+
+	return p.buffer.PeekN(n), nil
+}
+
+// Unread pushes v back so that it is returned by the next call to Next or Peek
+func (p *PeekableIterator[V]) Unread(v V) {
+	p.buffer.PushFront(v)
+}
+
+// Reset discards any lookahead and resets the underlying iterator
+func (p *PeekableIterator[V]) Reset() error {
+	p.buffer = queue.Queue[V]{}
+	p.err = nil
+
+	return p.in.Reset()
+}
+
+func (p *PeekableIterator[V]) Close() error {
+	return Close(p.in)
+}
+
+var _ Iterator[int] = &PeekableIterator[int]{}
@@ -0,0 +1,144 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucas-s-work/funcy-go/tuple"
+)
+
+type skipErrorsIterator[V any] struct {
+	Iterator[V]
+	onErr func(error)
+}
+
+// SkipErrors drops values produced with an error, calling onErr with the error if it is not nil.
+// Sources which never stop producing errors, such as a ReadIterator at EOF, should be bounded with
+// StopAfterNErrors instead.
+func SkipErrors[V any](i Iterator[V], onErr func(error)) Iterator[V] {
+	return &skipErrorsIterator[V]{
+		Iterator: i,
+		onErr:    onErr,
+	}
+}
+
+func (s *skipErrorsIterator[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *skipErrorsIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	for {
+		v, err, ok := pull(ctx, s.Iterator)
+		if !ok {
+			return v, nil, false
+		}
+		if err == nil {
+			return v, nil, true
+		}
+		// Cancellation ends the iteration rather than being skipped like a failed value
+		if cerr := ctx.Err(); cerr != nil {
+			var o V
+			return o, cerr, true
+		}
+
+		if s.onErr != nil {
+			s.onErr(err)
+		}
+	}
+}
+
+func (s *skipErrorsIterator[V]) Close() error {
+	return Close(s.Iterator)
+}
+
+// CollectErrors collects every value produced without an error, the failures are joined into the
+// returned error.
+func CollectErrors[V any](i Iterator[V]) ([]V, error) {
+	var errs []error
+	out, err := Collect(SkipErrors(i, func(err error) {
+		errs = append(errs, err)
+	}))
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return out, errors.Join(errs...)
+}
+
+type stopAfterErrorsIterator[V any] struct {
+	Iterator[V]
+	limit   int
+	errs    []error
+	stopped bool
+}
+
+// StopAfterNErrors skips values produced with an error until n errors have been seen, at which
+// point all of them are returned joined together and iteration stops.
+func StopAfterNErrors[V any](i Iterator[V], n int) Iterator[V] {
+	return &stopAfterErrorsIterator[V]{
+		Iterator: i,
+		limit:    n,
+	}
+}
+
+func (s *stopAfterErrorsIterator[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *stopAfterErrorsIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	var o V
+	if s.stopped {
+		return o, nil, false
+	}
+
+	for {
+		v, err, ok := pull(ctx, s.Iterator)
+		if !ok {
+			return v, nil, false
+		}
+		if err == nil {
+			return v, nil, true
+		}
+		if cerr := ctx.Err(); cerr != nil {
+			return o, cerr, true
+		}
+
+		s.errs = append(s.errs, err)
+		if len(s.errs) >= s.limit {
+			s.stopped = true
+			return o, errors.Join(s.errs...), true
+		}
+	}
+}
+
+func (s *stopAfterErrorsIterator[V]) Reset() error {
+	if err := s.Iterator.Reset(); err != nil {
+		return err
+	}
+
+	s.errs = nil
+	s.stopped = false
+	return nil
+}
+
+func (s *stopAfterErrorsIterator[V]) Close() error {
+	return Close(s.Iterator)
+}
+
+// Failure is a value which was produced alongside an error
+type Failure[V any] struct {
+	Value V
+	Err   error
+}
+
+// DeadLetter splits i into the values produced successfully and the values which failed along with
+// their error. Values are buffered in the style of Partition until the other side pulls them.
+func DeadLetter[V any](i Iterator[V]) (Iterator[V], Iterator[Failure[V]]) {
+	return newFork(i, func(v V, err error) (tuple.Option[V], tuple.Option[Failure[V]], error) {
+		if err != nil {
+			return tuple.None[V](), tuple.Some(Failure[V]{Value: v, Err: err}), nil
+		}
+
+		return tuple.Some(v), tuple.None[Failure[V]](), nil
+	}, "DeadLetter(passed)", "DeadLetter(failed)")
+}
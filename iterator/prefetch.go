@@ -0,0 +1,97 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+)
+
+type prefetchIterator[V any] struct {
+	workerPool
+	in     Iterator[V]
+	size   int
+	buffer chan result[V]
+}
+
+// Prefetch pulls from i on a background goroutine into a buffer of up to n values, so a slow source
+// such as a ReadIterator overlaps with the consumer. Close should be called if the values aren't
+// drained so the goroutine can exit, Each, Collect and First do this automatically. Closing
+// interrupts a pending pull from i if it supports cancellation, such as a ChanIterator, otherwise
+// it waits for the pull to return.
+func Prefetch[V any](i Iterator[V], n int) Iterator[V] {
+	if n < 1 {
+		n = 1
+	}
+
+	return &prefetchIterator[V]{
+		in:   i,
+		size: n,
+	}
+}
+
+func (p *prefetchIterator[V]) start() {
+	p.buffer = make(chan result[V], p.size)
+
+	feed := func() {
+		defer close(p.buffer)
+
+		for {
+			v, err, ok := pull(p.ctx, p.in)
+			select {
+			case p.buffer <- result[V]{v: v, err: err, ok: ok}:
+			case <-p.ctx.Done():
+				return
+			}
+			if !ok {
+				return
+			}
+		}
+	}
+
+	p.workerPool.start(0, feed, nil, func() {})
+}
+
+func (p *prefetchIterator[V]) Next() (V, error, bool) {
+	return p.nextContext(context.Background())
+}
+
+func (p *prefetchIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	var o V
+	if p.done {
+		return o, nil, false
+	}
+	if !p.started {
+		p.start()
+	}
+
+	select {
+	case r, ok := <-p.buffer:
+		if !ok || !r.ok {
+			p.shutdown()
+			return o, nil, false
+		}
+
+		return r.v, r.err, true
+	case <-ctx.Done():
+		return o, ctx.Err(), true
+	}
+}
+
+// Reset discards any buffered values before resetting the source
+func (p *prefetchIterator[V]) Reset() error {
+	p.shutdown()
+	if err := p.in.Reset(); err != nil {
+		return err
+	}
+
+	p.done = false
+	return nil
+}
+
+func (p *prefetchIterator[V]) Close() error {
+	p.shutdown()
+	return Close(p.in)
+}
+
+func (p *prefetchIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Prefetch(%d)", p.size), []any{p.in}
+}
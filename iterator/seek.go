@@ -0,0 +1,179 @@
+package iterator
+
+import "fmt"
+
+// Seeker is implemented by index-backed iterators which can jump directly to a position rather
+// than pulling every value in between.
+type Seeker interface {
+	// Seek moves to the absolute position offset, which must be within [0, Len()]
+	Seek(offset int) error
+	Position() int
+	Len() int
+}
+
+func checkOffset(s Seeker, offset int) error {
+	if offset < 0 || offset > s.Len() {
+		return fmt.Errorf("seek offset %v out of range [0, %v]", offset, s.Len())
+	}
+
+	return nil
+}
+
+// seekBy moves s forward by n, stopping at the end
+func seekBy(s Seeker, n int) error {
+	pos := s.Position() + n
+	if l := s.Len(); pos > l {
+		pos = l
+	}
+
+	return s.Seek(pos)
+}
+
+func (s *SliceIterator[V]) Seek(offset int) error {
+	if err := checkOffset(s, offset); err != nil {
+		return err
+	}
+
+	s.index = offset
+	return nil
+}
+
+func (s *SliceIterator[V]) Position() int {
+	return s.index
+}
+
+func (s *SliceIterator[V]) Len() int {
+	return len(s.s)
+}
+
+func (s *StringIterator) Seek(offset int) error {
+	if err := checkOffset(s, offset); err != nil {
+		return err
+	}
+
+	s.index = offset
+	return nil
+}
+
+func (s *StringIterator) Position() int {
+	return s.index
+}
+
+func (s *StringIterator) Len() int {
+	return len(s.s)
+}
+
+func (m *MatrixIterator[V]) Seek(offset int) error {
+	if err := checkOffset(m, offset); err != nil {
+		return err
+	}
+
+	m.dim1index = 0
+	for m.dim1index < len(m.m) && offset > len(m.m[m.dim1index]) {
+		offset -= len(m.m[m.dim1index])
+		m.dim1index++
+	}
+	m.dim2index = offset
+
+	return nil
+}
+
+func (m *MatrixIterator[V]) Position() int {
+	pos := 0
+	for i := 0; i < m.dim1index && i < len(m.m); i++ {
+		pos += len(m.m[i])
+	}
+
+	return pos + m.dim2index
+}
+
+func (m *MatrixIterator[V]) Len() int {
+	l := 0
+	for _, row := range m.m {
+		l += len(row)
+	}
+
+	return l
+}
+
+var _ Seeker = &SliceIterator[int]{}
+var _ Seeker = &StringIterator{}
+var _ Seeker = &MatrixIterator[int]{}
+
+type seekableMappedIterator[I, O any] struct {
+	*mappedIterator[I, O]
+	seeker Seeker
+}
+
+func (m *seekableMappedIterator[I, O]) Seek(offset int) error {
+	return m.seeker.Seek(offset)
+}
+
+func (m *seekableMappedIterator[I, O]) Position() int {
+	return m.seeker.Position()
+}
+
+func (m *seekableMappedIterator[I, O]) Len() int {
+	return m.seeker.Len()
+}
+
+// Positions are relative to the start of the limit rather than the underlying source
+type seekableLimitedIterator[V any] struct {
+	*LimitedIterator[V]
+	seeker Seeker
+}
+
+func (l *seekableLimitedIterator[V]) Seek(offset int) error {
+	if err := checkOffset(l, offset); err != nil {
+		return err
+	}
+	if err := l.seeker.Seek(l.seeker.Position() - l.index + offset); err != nil {
+		return err
+	}
+
+	l.index = offset
+	return nil
+}
+
+func (l *seekableLimitedIterator[V]) Position() int {
+	return l.index
+}
+
+func (l *seekableLimitedIterator[V]) Len() int {
+	n := l.index + l.seeker.Len() - l.seeker.Position()
+	if n > l.limit {
+		return l.limit
+	}
+
+	return n
+}
+
+// Nth returns the nth value of i counting from 0, seeking directly to it when i is a Seeker. There
+// is no value for a negative n, i is left untouched.
+func Nth[V any](i Iterator[V], n int) (V, error, bool) {
+	if n < 0 {
+		var o V
+		return o, nil, false
+	}
+	if s, ok := i.(Seeker); ok {
+		if s.Position()+n >= s.Len() {
+			var o V
+			return o, nil, false
+		}
+		if err := seekBy(s, n); err != nil {
+			var o V
+			return o, err, true
+		}
+
+		return i.Next()
+	}
+
+	for j := 0; j < n; j++ {
+		v, err, ok := i.Next()
+		if !ok || err != nil {
+			return v, err, ok
+		}
+	}
+
+	return i.Next()
+}
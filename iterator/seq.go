@@ -0,0 +1,161 @@
+package iterator
+
+import (
+	"fmt"
+	"iter"
+)
+
+// All adapts an Iterator to a range-over-func sequence, yielding every value alongside the error
+// produced with it. Sources which keep returning errors (such as a ReadIterator at EOF) will loop
+// forever unless the caller breaks out on error.
+func All[V any](i Iterator[V]) iter.Seq2[V, error] {
+	return func(yield func(V, error) bool) {
+		for {
+			v, err, ok := i.Next()
+			if !ok {
+				return
+			}
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// Values adapts an Iterator to a range-over-func sequence of values, stopping at the first error.
+// Use All if the error needs to be observed.
+func Values[V any](i Iterator[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for {
+			v, err, ok := i.Next()
+			if !ok || err != nil {
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+type seqIterator[V any] struct {
+	seq  iter.Seq2[V, error]
+	next func() (V, error, bool)
+	stop func()
+	done bool
+}
+
+// FromSeq wraps a standard library sequence such as maps.Keys or slices.Values, the sequence is
+// pulled lazily and restarted on Reset.
+func FromSeq[V any](s iter.Seq[V]) Iterator[V] {
+	return FromSeq2(func(yield func(V, error) bool) {
+		for v := range s {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	})
+}
+
+// FromSeq2 wraps a sequence of values and errors, such as one produced by All.
+func FromSeq2[V any](s iter.Seq2[V, error]) Iterator[V] {
+	return &seqIterator[V]{
+		seq: s,
+	}
+}
+
+func (s *seqIterator[V]) Next() (V, error, bool) {
+	if s.done {
+		var o V
+		return o, nil, false
+	}
+	if s.next == nil {
+		s.next, s.stop = iter.Pull2(s.seq)
+	}
+
+	v, err, ok := s.next()
+	if !ok {
+		// Release the underlying coroutine as soon as the sequence is exhausted
+		s.Close()
+		s.done = true
+	}
+
+	return v, err, ok
+}
+
+func (s *seqIterator[V]) Reset() error {
+	s.Close()
+	s.next = nil
+	s.done = false
+
+	return nil
+}
+
+// Close stops the underlying sequence, releasing any resources it holds.
+func (s *seqIterator[V]) Close() error {
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+
+	return nil
+}
+
+var _ Iterator[int] = &seqIterator[int]{}
+
+type pullIterator[V any] struct {
+	next func() (V, error, bool)
+	stop func()
+	done bool
+}
+
+// FromPull wraps a pull function pair as returned by iter.Pull, stop is called once the values are
+// exhausted or the iterator is closed.
+func FromPull[V any](next func() (V, bool), stop func()) Iterator[V] {
+	return FromPull2(func() (V, error, bool) {
+		v, ok := next()
+		return v, nil, ok
+	}, stop)
+}
+
+// FromPull2 wraps a pull function which also produces errors.
+func FromPull2[V any](next func() (V, error, bool), stop func()) Iterator[V] {
+	return &pullIterator[V]{
+		next: next,
+		stop: stop,
+	}
+}
+
+func (p *pullIterator[V]) Next() (V, error, bool) {
+	if p.done {
+		var o V
+		return o, nil, false
+	}
+
+	v, err, ok := p.next()
+	if !ok {
+		p.Close()
+	}
+
+	return v, err, ok
+}
+
+func (p *pullIterator[V]) Reset() error {
+	return fmt.Errorf("cannot reset pull iterator")
+}
+
+// Close stops the underlying pull function, further calls to Next will report no values.
+func (p *pullIterator[V]) Close() error {
+	if p.done {
+		return nil
+	}
+
+	p.done = true
+	if p.stop != nil {
+		p.stop()
+	}
+
+	return nil
+}
+
+var _ Iterator[int] = &pullIterator[int]{}
@@ -0,0 +1,416 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+)
+
+type takeWhileIterator[V any] struct {
+	Iterator[V]
+	check     func(V) (bool, error)
+	inclusive bool
+	done      bool
+}
+
+// TakeWhile yields values until c reports false for one, which is dropped
+func TakeWhile[V any](i Iterator[V], c func(V) (bool, error)) Iterator[V] {
+	return &takeWhileIterator[V]{
+		Iterator: i,
+		check:    c,
+	}
+}
+
+// TakeUntil yields values up to and including the first one for which c reports true
+func TakeUntil[V any](i Iterator[V], c func(V) (bool, error)) Iterator[V] {
+	return &takeWhileIterator[V]{
+		Iterator: i,
+		check: func(v V) (bool, error) {
+			ok, err := c(v)
+			return !ok, err
+		},
+		inclusive: true,
+	}
+}
+
+func (t *takeWhileIterator[V]) Next() (V, error, bool) {
+	return t.nextContext(context.Background())
+}
+
+func (t *takeWhileIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	var o V
+	if t.done {
+		return o, nil, false
+	}
+
+	v, err, ok := pull(ctx, t.Iterator)
+	if !ok {
+		return v, nil, false
+	}
+	if err != nil {
+		return v, err, true
+	}
+
+	ok, err = t.check(v)
+	if err != nil {
+		return o, err, true
+	}
+	if !ok {
+		t.done = true
+		if t.inclusive {
+			return v, nil, true
+		}
+
+		return o, nil, false
+	}
+
+	return v, nil, true
+}
+
+func (t *takeWhileIterator[V]) Reset() error {
+	if err := t.Iterator.Reset(); err != nil {
+		return err
+	}
+
+	t.done = false
+	return nil
+}
+
+func (t *takeWhileIterator[V]) Close() error {
+	return Close(t.Iterator)
+}
+
+func (t *takeWhileIterator[V]) SizeHint() (int, int, bool) {
+	if t.done {
+		return exactHint(0)
+	}
+
+	_, max, _ := SizeHint(t.Iterator)
+	return 0, max, max == 0
+}
+
+func (t *takeWhileIterator[V]) stage() (string, []any) {
+	if t.inclusive {
+		return "TakeUntil", []any{t.Iterator}
+	}
+
+	return "TakeWhile", []any{t.Iterator}
+}
+
+type dropWhileIterator[V any] struct {
+	Iterator[V]
+	check    func(V) (bool, error)
+	dropping bool
+}
+
+// DropWhile drops values until c reports false for one, after which every value is yielded
+func DropWhile[V any](i Iterator[V], c func(V) (bool, error)) Iterator[V] {
+	return &dropWhileIterator[V]{
+		Iterator: i,
+		check:    c,
+		dropping: true,
+	}
+}
+
+func (d *dropWhileIterator[V]) Next() (V, error, bool) {
+	return d.nextContext(context.Background())
+}
+
+func (d *dropWhileIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	for d.dropping {
+		v, err, ok := pull(ctx, d.Iterator)
+		if !ok {
+			return v, nil, false
+		}
+		if err != nil {
+			return v, err, true
+		}
+
+		ok, err = d.check(v)
+		if err != nil {
+			var o V
+			return o, err, true
+		}
+		if !ok {
+			d.dropping = false
+			return v, nil, true
+		}
+	}
+
+	return pull(ctx, d.Iterator)
+}
+
+func (d *dropWhileIterator[V]) Reset() error {
+	if err := d.Iterator.Reset(); err != nil {
+		return err
+	}
+
+	d.dropping = true
+	return nil
+}
+
+func (d *dropWhileIterator[V]) Close() error {
+	return Close(d.Iterator)
+}
+
+func (d *dropWhileIterator[V]) SizeHint() (int, int, bool) {
+	if !d.dropping {
+		return SizeHint(d.Iterator)
+	}
+
+	_, max, _ := SizeHint(d.Iterator)
+	return 0, max, max == 0
+}
+
+func (d *dropWhileIterator[V]) stage() (string, []any) {
+	return "DropWhile", []any{d.Iterator}
+}
+
+type skipIterator[V any] struct {
+	Iterator[V]
+	n int
+	// remaining counts the values still to be skipped before the first value is yielded
+	remaining int
+}
+
+// Skip lazily drops the first n values of i, seeking past them when i is a Seeker. Errors from
+// the skipped values are still reported.
+func Skip[V any](i Iterator[V], n int) Iterator[V] {
+	s := &skipIterator[V]{
+		Iterator:  i,
+		n:         n,
+		remaining: n,
+	}
+	if seeker, ok := i.(Seeker); ok {
+		return &seekableSkipIterator[V]{
+			skipIterator: s,
+			seeker:       seeker,
+		}
+	}
+
+	return s
+}
+
+func (s *skipIterator[V]) skip(ctx context.Context) error {
+	if seeker, ok := s.Iterator.(Seeker); ok && s.remaining > 0 {
+		s.remaining = 0
+		return seekBy(seeker, s.n)
+	}
+
+	for s.remaining > 0 {
+		_, err, ok := pull(ctx, s.Iterator)
+		if !ok {
+			s.remaining = 0
+			return nil
+		}
+
+		s.remaining--
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *skipIterator[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *skipIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	if err := s.skip(ctx); err != nil {
+		var o V
+		return o, err, true
+	}
+
+	return pull(ctx, s.Iterator)
+}
+
+func (s *skipIterator[V]) Reset() error {
+	if err := s.Iterator.Reset(); err != nil {
+		return err
+	}
+
+	s.remaining = s.n
+	return nil
+}
+
+func (s *skipIterator[V]) Close() error {
+	return Close(s.Iterator)
+}
+
+func (s *skipIterator[V]) SizeHint() (int, int, bool) {
+	min, max, exact := SizeHint(s.Iterator)
+	min -= s.remaining
+	if min < 0 {
+		min = 0
+	}
+	if max > 0 {
+		max -= s.remaining
+		if max < 0 {
+			max = 0
+		}
+	}
+
+	return min, max, exact
+}
+
+func (s *skipIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Skip(%d)", s.n), []any{s.Iterator}
+}
+
+// Positions are relative to the first value after those skipped
+type seekableSkipIterator[V any] struct {
+	*skipIterator[V]
+	seeker Seeker
+	start  int
+}
+
+func (s *seekableSkipIterator[V]) ensureSkipped() {
+	if s.remaining > 0 {
+		// Seeking can only fail here if the source is broken, which Next will then report
+		_ = s.skip(context.Background())
+		s.start = s.seeker.Position()
+	}
+}
+
+func (s *seekableSkipIterator[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *seekableSkipIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	s.ensureSkipped()
+	return s.skipIterator.nextContext(ctx)
+}
+
+func (s *seekableSkipIterator[V]) Seek(offset int) error {
+	s.ensureSkipped()
+	if err := checkOffset(s, offset); err != nil {
+		return err
+	}
+
+	return s.seeker.Seek(s.start + offset)
+}
+
+func (s *seekableSkipIterator[V]) Position() int {
+	s.ensureSkipped()
+	return s.seeker.Position() - s.start
+}
+
+func (s *seekableSkipIterator[V]) Len() int {
+	s.ensureSkipped()
+	return s.seeker.Len() - s.start
+}
+
+type stepIterator[V any] struct {
+	Iterator[V]
+	step    int
+	started bool
+}
+
+// StepBy yields the first value of i and then every kth value after it
+func StepBy[V any](i Iterator[V], k int) Iterator[V] {
+	if k < 1 {
+		k = 1
+	}
+
+	return &stepIterator[V]{
+		Iterator: i,
+		step:     k,
+	}
+}
+
+func (s *stepIterator[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *stepIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	if !s.started {
+		s.started = true
+		return pull(ctx, s.Iterator)
+	}
+
+	if seeker, ok := s.Iterator.(Seeker); ok {
+		if err := seekBy(seeker, s.step-1); err != nil {
+			var o V
+			return o, err, true
+		}
+
+		return pull(ctx, s.Iterator)
+	}
+
+	for j := 0; j < s.step-1; j++ {
+		v, err, ok := pull(ctx, s.Iterator)
+		if !ok || err != nil {
+			return v, err, ok
+		}
+	}
+
+	return pull(ctx, s.Iterator)
+}
+
+func (s *stepIterator[V]) Reset() error {
+	if err := s.Iterator.Reset(); err != nil {
+		return err
+	}
+
+	s.started = false
+	return nil
+}
+
+func (s *stepIterator[V]) Close() error {
+	return Close(s.Iterator)
+}
+
+func (s *stepIterator[V]) SizeHint() (int, int, bool) {
+	stepped := func(n int) int {
+		if n < 0 {
+			return n
+		}
+		if s.started {
+			// The next value is behind step - 1 skipped ones
+			n = max(n-(s.step-1), 0)
+		}
+
+		// Rounded up for the value which starts each step
+		return ceilDiv(n, s.step)
+	}
+
+	lo, hi, exact := SizeHint(s.Iterator)
+	return stepped(lo), stepped(hi), exact
+}
+
+func (s *stepIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("StepBy(%d)", s.step), []any{s.Iterator}
+}
+
+// Last returns the final value of i, closing i once it is found. Iterators which are both
+// seekable and bidirectional are read from their end directly.
+func Last[V any](i Iterator[V]) (V, error, bool) {
+	s, seekable := i.(Seeker)
+	b, bidirectional := i.(Bidirectional[V])
+	if seekable && bidirectional && s.Position() < s.Len() {
+		if err := s.Seek(s.Len()); err != nil {
+			var o V
+			return o, err, true
+		}
+
+		v, err, ok := b.Prev()
+		if cerr := Close(i); err == nil {
+			err = cerr
+		}
+		return v, err, ok
+	}
+
+	var last V
+	found := false
+	if err := Each(i, func(v V) error {
+		last = v
+		found = true
+		return nil
+	}); err != nil {
+		var o V
+		return o, err, true
+	}
+
+	return last, nil, found
+}
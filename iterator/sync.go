@@ -0,0 +1,75 @@
+package iterator
+
+import (
+	"context"
+	"sync"
+)
+
+type synchronizedIterator[V any] struct {
+	mu sync.Mutex
+	in Iterator[V]
+}
+
+// Synchronized serialises calls to i so it can be shared between goroutines, each value is
+// delivered to exactly one caller of Next.
+func Synchronized[V any](i Iterator[V]) Iterator[V] {
+	return &synchronizedIterator[V]{
+		in: i,
+	}
+}
+
+func (s *synchronizedIterator[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *synchronizedIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return pull(ctx, s.in)
+}
+
+func (s *synchronizedIterator[V]) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.in.Reset()
+}
+
+// Close is a no-op, consumers sharing the iterator each close it when they finish so the source
+// is left for its owner to close once every consumer is done.
+func (s *synchronizedIterator[V]) Close() error {
+	return nil
+}
+
+func (s *synchronizedIterator[V]) SizeHint() (int, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SizeHint(s.in)
+}
+
+func (s *synchronizedIterator[V]) stage() (string, []any) {
+	return "Synchronized", []any{s.in}
+}
+
+// WorkSource returns a pull function which a pool of goroutines can share to take work items from
+// i. Once i is exhausted it is never pulled from again, every later call reports no values.
+func WorkSource[V any](i Iterator[V]) func() (V, error, bool) {
+	var mu sync.Mutex
+	done := false
+
+	return func() (V, error, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if done {
+			var o V
+			return o, nil, false
+		}
+
+		v, err, ok := i.Next()
+		done = !ok
+		return v, err, ok
+	}
+}
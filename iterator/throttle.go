@@ -0,0 +1,167 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type throttleIterator[V any] struct {
+	Iterator[V]
+	clock   Clock
+	rate    float64
+	burst   int
+	tokens  float64
+	last    time.Time
+	started bool
+	err     error
+}
+
+// Throttle limits the rate values are pulled from i to rate per second using a token bucket which
+// allows bursts of up to burst values. The wait happens before pulling from i, so the work done
+// by i, such as a Map calling another service, is itself rate limited. A rate of zero or less is
+// rejected, every call to Next then reports the error without pulling from i.
+func Throttle[V any](i Iterator[V], rate float64, burst int) Iterator[V] {
+	return ThrottleWithClock(i, rate, burst, SystemClock)
+}
+
+func ThrottleWithClock[V any](i Iterator[V], rate float64, burst int, clock Clock) Iterator[V] {
+	if burst < 1 {
+		burst = 1
+	}
+
+	var err error
+	if rate <= 0 {
+		err = fmt.Errorf("throttle rate must be positive, got %g", rate)
+	}
+
+	return &throttleIterator[V]{
+		Iterator: i,
+		clock:    clock,
+		rate:     rate,
+		burst:    burst,
+		err:      err,
+	}
+}
+
+func (t *throttleIterator[V]) refill() {
+	now := t.clock.Now()
+	if !t.started {
+		t.started = true
+		t.tokens = float64(t.burst)
+		t.last = now
+	}
+
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > float64(t.burst) {
+		t.tokens = float64(t.burst)
+	}
+	t.last = now
+}
+
+func (t *throttleIterator[V]) Next() (V, error, bool) {
+	return t.nextContext(context.Background())
+}
+
+func (t *throttleIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	var o V
+	if t.err != nil {
+		return o, t.err, true
+	}
+
+	for t.refill(); t.tokens < 1; t.refill() {
+		wait := time.Duration((1 - t.tokens) / t.rate * float64(time.Second))
+		select {
+		case <-t.clock.After(wait):
+		case <-ctx.Done():
+			return o, ctx.Err(), true
+		}
+	}
+	t.tokens--
+
+	return pull(ctx, t.Iterator)
+}
+
+func (t *throttleIterator[V]) Reset() error {
+	if err := t.Iterator.Reset(); err != nil {
+		return err
+	}
+
+	t.started = false
+	return nil
+}
+
+func (t *throttleIterator[V]) Close() error {
+	return Close(t.Iterator)
+}
+
+func (t *throttleIterator[V]) SizeHint() (int, int, bool) {
+	return SizeHint(t.Iterator)
+}
+
+func (t *throttleIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Throttle(%g/s, %d)", t.rate, t.burst), []any{t.Iterator}
+}
+
+type minIntervalIterator[V any] struct {
+	Iterator[V]
+	clock    Clock
+	interval time.Duration
+	last     time.Time
+	started  bool
+}
+
+// MinInterval spaces the pulls from i so at least d passes between each of them
+func MinInterval[V any](i Iterator[V], d time.Duration) Iterator[V] {
+	return MinIntervalWithClock(i, d, SystemClock)
+}
+
+func MinIntervalWithClock[V any](i Iterator[V], d time.Duration, clock Clock) Iterator[V] {
+	return &minIntervalIterator[V]{
+		Iterator: i,
+		clock:    clock,
+		interval: d,
+	}
+}
+
+func (m *minIntervalIterator[V]) Next() (V, error, bool) {
+	return m.nextContext(context.Background())
+}
+
+func (m *minIntervalIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
+	if m.started {
+		if wait := m.interval - m.clock.Now().Sub(m.last); wait > 0 {
+			select {
+			case <-m.clock.After(wait):
+			case <-ctx.Done():
+				var o V
+				return o, ctx.Err(), true
+			}
+		}
+	}
+
+	m.started = true
+	m.last = m.clock.Now()
+	return pull(ctx, m.Iterator)
+}
+
+func (m *minIntervalIterator[V]) Reset() error {
+	if err := m.Iterator.Reset(); err != nil {
+		return err
+	}
+
+	m.started = false
+	return nil
+}
+
+func (m *minIntervalIterator[V]) Close() error {
+	return Close(m.Iterator)
+}
+
+func (m *minIntervalIterator[V]) SizeHint() (int, int, bool) {
+	return SizeHint(m.Iterator)
+}
+
+func (m *minIntervalIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("MinInterval(%v)", m.interval), []any{m.Iterator}
+}
@@ -1,9 +1,11 @@
 package iterator
 
 import (
+	"context"
+	"errors"
+
 	"github.com/lucas-s-work/funcy-go/queue"
 	"github.com/lucas-s-work/funcy-go/slice"
-	"github.com/lucas-s-work/funcy-go/stack"
 	"golang.org/x/exp/constraints"
 )
 
@@ -13,7 +15,12 @@ func Times(n int, f func()) {
 	}
 }
 
+// Each calls f with every value of i, closing i once iteration stops
 func Each[V any](i Iterator[V], f func(V) error) error {
+	return errors.Join(each(i, f), Close(i))
+}
+
+func each[V any](i Iterator[V], f func(V) error) error {
 	for {
 		v, err, ok := i.Next()
 		if !ok {
@@ -31,6 +38,9 @@ func Each[V any](i Iterator[V], f func(V) error) error {
 
 func Collect[V any](i Iterator[V]) ([]V, error) {
 	var out []V
+	if n := capacityHint(i); n > 0 {
+		out = make([]V, 0, n)
+	}
 	if err := Each(i, func(v V) error {
 		out = append(out, v)
 
@@ -43,25 +53,32 @@ func Collect[V any](i Iterator[V]) ([]V, error) {
 }
 
 func CollectWithLimit[V any](i Iterator[V], limit int) ([]V, error) {
-	var out []V
-	if err := Each(WithLimit(i, limit), func(v V) error {
-		out = append(out, v)
-
-		return nil
-	}); err != nil {
-		return nil, err
-	}
-
-	return out, nil
+	return Collect(WithLimit(i, limit))
 }
 
 type mappedIterator[I, O any] struct {
 	Iterator[I]
 	action func(I) (O, error)
+	obs    *observer
 }
 
 func (m *mappedIterator[I, O]) Next() (O, error, bool) {
-	v, err, ok := m.Iterator.Next()
+	return m.nextContext(context.Background())
+}
+
+func (m *mappedIterator[I, O]) nextContext(ctx context.Context) (O, error, bool) {
+	if m.obs != nil {
+		return observeNext(m.obs, func() (O, error, bool) { return m.next(ctx) })
+	}
+
+	return m.next(ctx)
+}
+
+func (m *mappedIterator[I, O]) next(ctx context.Context) (O, error, bool) {
+	return m.apply(pull(ctx, m.Iterator))
+}
+
+func (m *mappedIterator[I, O]) apply(v I, err error, ok bool) (O, error, bool) {
 	var o O
 	if !ok {
 		return o, nil, ok
@@ -75,20 +92,65 @@ func (m *mappedIterator[I, O]) Next() (O, error, bool) {
 }
 
 func Map[I, O any](i Iterator[I], f func(I) (O, error)) Iterator[O] {
-	return &mappedIterator[I, O]{
+	m := &mappedIterator[I, O]{
 		Iterator: i,
 		action:   f,
 	}
+	s, seekable := i.(Seeker)
+	b, bidirectional := i.(Bidirectional[I])
+	switch {
+	case seekable && bidirectional:
+		return &indexedMappedIterator[I, O]{
+			seekableMappedIterator: &seekableMappedIterator[I, O]{
+				mappedIterator: m,
+				seeker:         s,
+			},
+			bidi: b,
+		}
+	case seekable:
+		return &seekableMappedIterator[I, O]{
+			mappedIterator: m,
+			seeker:         s,
+		}
+	case bidirectional:
+		return &bidirectionalMappedIterator[I, O]{
+			mappedIterator: m,
+			bidi:           b,
+		}
+	}
+
+	return m
+}
+
+func (m *mappedIterator[I, O]) Close() error {
+	return Close(m.Iterator)
+}
+
+func (m *mappedIterator[I, O]) SizeHint() (int, int, bool) {
+	return SizeHint(m.Iterator)
 }
 
 type filterIterator[I any] struct {
 	Iterator[I]
 	check func(I) (bool, error)
+	obs   *observer
 }
 
 func (f *filterIterator[I]) Next() (I, error, bool) {
+	return f.nextContext(context.Background())
+}
+
+func (f *filterIterator[I]) nextContext(ctx context.Context) (I, error, bool) {
+	if f.obs != nil {
+		return observeNext(f.obs, func() (I, error, bool) { return f.next(ctx) })
+	}
+
+	return f.next(ctx)
+}
+
+func (f *filterIterator[I]) next(ctx context.Context) (I, error, bool) {
 	for {
-		v, err, ok := f.Iterator.Next()
+		v, err, ok := pull(ctx, f.Iterator)
 		if !ok {
 			return v, nil, false
 		}
@@ -104,6 +166,9 @@ func (f *filterIterator[I]) Next() (I, error, bool) {
 			var o I
 			return o, err, true
 		}
+		if f.obs != nil {
+			f.obs.ObserveDrop(f.obs.stage)
+		}
 	}
 }
 
@@ -114,6 +179,16 @@ func Filter[I any](i Iterator[I], c func(I) (bool, error)) Iterator[I] {
 	}
 }
 
+func (f *filterIterator[I]) Close() error {
+	return Close(f.Iterator)
+}
+
+// Any number of values may be filtered out, so only the upper bound is kept
+func (f *filterIterator[I]) SizeHint() (int, int, bool) {
+	_, max, _ := SizeHint(f.Iterator)
+	return 0, max, max == 0
+}
+
 type ConsIterator[V any, O Iterator[V]] struct {
 	Iterator[V]
 	stride int
@@ -129,12 +204,17 @@ func Cons[I any](i Iterator[I], stride int) Iterator[Iterator[I]] {
 }
 
 func (c *ConsIterator[I, O]) Next() (Iterator[I], error, bool) {
+	return c.nextContext(context.Background())
+}
+
+func (c *ConsIterator[I, O]) nextContext(ctx context.Context) (Iterator[I], error, bool) {
 	if c.empty {
 		return nil, nil, false
 	}
-	var out []I
+	// Only reserve what the source is known to hold, the stride can be arbitrarily large
+	out := make([]I, 0, min(c.stride, capacityHint(c.Iterator)))
 	for i := 0; i < c.stride; i++ {
-		v, err, ok := c.Iterator.Next()
+		v, err, ok := pull(ctx, c.Iterator)
 		if !ok {
 			c.empty = true
 			if len(out) == 0 {
@@ -159,7 +239,25 @@ func (c *ConsIterator[I, O]) Reset() error {
 	return c.Iterator.Reset()
 }
 
+func (c *ConsIterator[I, O]) Close() error {
+	return Close(c.Iterator)
+}
+
+func (c *ConsIterator[I, O]) SizeHint() (int, int, bool) {
+	if c.empty {
+		return exactHint(0)
+	}
+
+	min, max, exact := SizeHint(c.Iterator)
+	return ceilDiv(min, c.stride), ceilDiv(max, c.stride), exact
+}
+
+// Fold reports each step to the Observer of pipelines wrapped by Instrument
 func Fold[I, O any](i Iterator[I], acc O, f func(I, O) (O, error)) (O, error) {
+	if obs := foldObserver(i); obs != nil {
+		f = observeFold(obs, f)
+	}
+
 	if err := Each(i, func(v I) error {
 		var err error
 		acc, err = f(v, acc)
@@ -187,7 +285,11 @@ func Scan[I, O any](i Iterator[I], acc O, f func(I, O) (O, error)) Iterator[O] {
 }
 
 func (s *scanIterator[I, O]) Next() (O, error, bool) {
-	v, err, ok := s.in.Next()
+	return s.nextContext(context.Background())
+}
+
+func (s *scanIterator[I, O]) nextContext(ctx context.Context) (O, error, bool) {
+	v, err, ok := pull(ctx, s.in)
 	var o O
 	if !ok {
 		return o, nil, false
@@ -213,6 +315,10 @@ func (s *scanIterator[I, O]) Reset() error {
 	return nil
 }
 
+func (s *scanIterator[I, O]) Close() error {
+	return Close(s.in)
+}
+
 func Sum[V constraints.Ordered](i Iterator[V]) (V, error) {
 	var acc V
 	return Fold(i, acc, func(v, acc V) (V, error) { return acc + v, nil })
@@ -252,8 +358,12 @@ func DistinctMap[V any, C constraints.Ordered](i Iterator[V], c func(V) C) Itera
 }
 
 func (d *distinctIterator[V, C]) Next() (V, error, bool) {
+	return d.nextContext(context.Background())
+}
+
+func (d *distinctIterator[V, C]) nextContext(ctx context.Context) (V, error, bool) {
 	for {
-		v, err, ok := d.in.Next()
+		v, err, ok := pull(ctx, d.in)
 		if !ok {
 			return v, nil, false
 		}
@@ -279,6 +389,10 @@ func (d *distinctIterator[V, C]) Reset() error {
 	return nil
 }
 
+func (d *distinctIterator[V, C]) Close() error {
+	return Close(d.in)
+}
+
 type mergeMapIterator[A, B, O any] struct {
 	in1 Iterator[A]
 	in2 Iterator[B]
@@ -294,15 +408,19 @@ func MergeMap[A, B, O any](a Iterator[A], b Iterator[B], f func(A, B) (O, error)
 }
 
 func (m *mergeMapIterator[A, B, O]) Next() (O, error, bool) {
+	return m.nextContext(context.Background())
+}
+
+func (m *mergeMapIterator[A, B, O]) nextContext(ctx context.Context) (O, error, bool) {
 	var o O
-	a, err, ok := m.in1.Next()
+	a, err, ok := pull(ctx, m.in1)
 	if !ok {
 		return o, nil, false
 	}
 	if err != nil {
 		return o, err, true
 	}
-	b, err, ok := m.in2.Next()
+	b, err, ok := pull(ctx, m.in2)
 	if !ok {
 		return o, nil, false
 	}
@@ -325,6 +443,26 @@ func (m *mergeMapIterator[A, B, O]) Reset() error {
 	return nil
 }
 
+func (m *mergeMapIterator[A, B, O]) Close() error {
+	return closeBoth(m.in1, m.in2)
+}
+
+// Values are only produced while both sources have values
+func (m *mergeMapIterator[A, B, O]) SizeHint() (int, int, bool) {
+	min1, max1, exact1 := SizeHint(m.in1)
+	min2, max2, exact2 := SizeHint(m.in2)
+
+	min, max := min1, max1
+	if min2 < min {
+		min = min2
+	}
+	if max < 0 || (max2 >= 0 && max2 < max) {
+		max = max2
+	}
+
+	return min, max, (exact1 && exact2) || min == max
+}
+
 type mergeIterator[V any] struct {
 	in1, in2 Iterator[V]
 	swap     bool
@@ -339,10 +477,14 @@ func Merge[I any](i1, i2 Iterator[I]) Iterator[I] {
 }
 
 func (m *mergeIterator[V]) Next() (V, error, bool) {
+	return m.nextContext(context.Background())
+}
+
+func (m *mergeIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
 	if m.swap {
-		v, err, ok := m.in2.Next()
+		v, err, ok := pull(ctx, m.in2)
 		if !ok {
-			return m.in1.Next()
+			return pull(ctx, m.in1)
 		}
 		if err != nil {
 			return v, err, true
@@ -352,9 +494,9 @@ func (m *mergeIterator[V]) Next() (V, error, bool) {
 		return v, err, ok
 	}
 
-	v, err, ok := m.in1.Next()
+	v, err, ok := pull(ctx, m.in1)
 	if !ok {
-		return m.in2.Next()
+		return pull(ctx, m.in2)
 	}
 	if err != nil {
 		return v, err, true
@@ -376,6 +518,10 @@ func (m *mergeIterator[V]) Reset() error {
 	return nil
 }
 
+func (m *mergeIterator[V]) Close() error {
+	return closeBoth(m.in1, m.in2)
+}
+
 // Alternatively use fold
 func Max[V constraints.Ordered](i Iterator[V]) (V, error) {
 	var m V
@@ -418,6 +564,8 @@ type split[V any] struct {
 	check   func(V) bool
 	partner *split[V]
 	cache   queue.Queue[V]
+	closed  bool
+	obs     *observer
 }
 
 func (s *split[V]) push(v V) {
@@ -442,6 +590,18 @@ func Partition[V any](i Iterator[V], check func(V) bool) (Iterator[V], Iterator[
 }
 
 func (s *split[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *split[V]) nextContext(ctx context.Context) (V, error, bool) {
+	if s.obs != nil {
+		return observeNext(s.obs, func() (V, error, bool) { return s.next(ctx) })
+	}
+
+	return s.next(ctx)
+}
+
+func (s *split[V]) next(ctx context.Context) (V, error, bool) {
 	// Pull off the cache first
 	v, ok := s.cache.Pop()
 	if ok {
@@ -450,7 +610,7 @@ func (s *split[V]) Next() (V, error, bool) {
 
 	// If the cache is empty iterate until we get a value or none is found
 	for {
-		v, err, ok := s.i.Next()
+		v, err, ok := pull(ctx, s.i)
 		if !ok {
 			return v, nil, false
 		}
@@ -477,6 +637,16 @@ func (s *split[V]) Reset() error {
 	return nil
 }
 
+// The shared source is only closed once both halves of the partition are closed
+func (s *split[V]) Close() error {
+	s.closed = true
+	if !s.partner.closed {
+		return nil
+	}
+
+	return Close(s.i)
+}
+
 func GroupBy[K constraints.Ordered, V any](i Iterator[V], f func(V) K) (map[K][]V, error) {
 	return Fold(i, make(map[K][]V), func(v V, m map[K][]V) (map[K][]V, error) {
 		k := f(v)
@@ -507,9 +677,13 @@ func Duplicate[V any](i Iterator[V], count int) Iterator[V] {
 }
 
 func (d *duplicateIterator[V]) Next() (V, error, bool) {
+	return d.nextContext(context.Background())
+}
+
+func (d *duplicateIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
 	if d.index == 0 {
 		d.index = d.count
-		v, err, ok := d.i.Next()
+		v, err, ok := pull(ctx, d.i)
 		if !ok {
 			return v, nil, false
 		}
@@ -532,6 +706,20 @@ func (d *duplicateIterator[V]) Reset() error {
 	return nil
 }
 
+func (d *duplicateIterator[V]) Close() error {
+	return Close(d.i)
+}
+
+func (d *duplicateIterator[V]) SizeHint() (int, int, bool) {
+	min, max, exact := SizeHint(d.i)
+	min = d.index + min*d.count
+	if max >= 0 {
+		max = d.index + max*d.count
+	}
+
+	return min, max, exact
+}
+
 type sortedIterator[V constraints.Ordered] struct {
 	Iterator[V]
 	in     Iterator[V]
@@ -557,14 +745,30 @@ func (s *sortedIterator[V]) Reset() error {
 	return nil
 }
 
+func (s *sortedIterator[V]) Close() error {
+	return Close(s.in)
+}
+
+func (s *sortedIterator[V]) SizeHint() (int, int, bool) {
+	if s.sorted && s.Iterator != nil {
+		return SizeHint(s.Iterator)
+	}
+
+	return SizeHint(s.in)
+}
+
 func (s *sortedIterator[V]) Next() (V, error, bool) {
+	return s.nextContext(context.Background())
+}
+
+func (s *sortedIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
 	if s.err != nil {
 		var o V
 		return o, s.err, true
 	}
 	if !s.sorted {
 		s.sorted = true
-		els, err := Collect(s.in)
+		els, err := Collect(WithContext(ctx, s.in))
 		if err != nil {
 			var o V
 			s.err = err
@@ -598,16 +802,17 @@ func Count[V any](i Iterator[V]) (int, error) {
 	})
 }
 
-func Skip[V any](i Iterator[V], n int) Iterator[V] {
-	// We don't care about the outputs here, the state is fixed if we end up in an error or empty state
-	Times(5, func() {
-		i.Next()
-	})
+// First returns the first value matching c, closing i once it is found or i is exhausted
+func First[V any](i Iterator[V], c func(v V) (bool, error)) (V, error, bool) {
+	v, err, ok := first(i, c)
+	if cerr := Close(i); cerr != nil {
+		return v, errors.Join(err, cerr), true
+	}
 
-	return i
+	return v, err, ok
 }
 
-func First[V any](i Iterator[V], c func(v V) (bool, error)) (V, error, bool) {
+func first[V any](i Iterator[V], c func(v V) (bool, error)) (V, error, bool) {
 	var o V
 	for {
 		v, err, ok := i.Next()
@@ -653,6 +858,7 @@ type bindIterator[I, O any] struct {
 	in     Iterator[I]
 	curr   Iterator[O]
 	mapper func(I) (Iterator[O], error)
+	obs    *observer
 }
 
 func Bind[I, O any](i Iterator[I], mapper func(I) (Iterator[O], error)) Iterator[O] {
@@ -662,8 +868,8 @@ func Bind[I, O any](i Iterator[I], mapper func(I) (Iterator[O], error)) Iterator
 	}
 }
 
-func (b *bindIterator[I, O]) mapNext() (error, bool) {
-	v, err, ok := b.in.Next()
+func (b *bindIterator[I, O]) mapNext(ctx context.Context) (error, bool) {
+	v, err, ok := pull(ctx, b.in)
 	if !ok {
 		return nil, false
 	}
@@ -676,15 +882,32 @@ func (b *bindIterator[I, O]) mapNext() (error, bool) {
 	if err != nil {
 		return err, true
 	}
+	if b.curr != nil {
+		if err := Close(b.curr); err != nil {
+			return err, true
+		}
+	}
 	b.curr = curr
 
 	return nil, true
 }
 
 func (b *bindIterator[I, O]) Next() (O, error, bool) {
+	return b.nextContext(context.Background())
+}
+
+func (b *bindIterator[I, O]) nextContext(ctx context.Context) (O, error, bool) {
+	if b.obs != nil {
+		return observeNext(b.obs, func() (O, error, bool) { return b.next(ctx) })
+	}
+
+	return b.next(ctx)
+}
+
+func (b *bindIterator[I, O]) next(ctx context.Context) (O, error, bool) {
 	var o O
 	if b.curr == nil {
-		err, ok := b.mapNext()
+		err, ok := b.mapNext(ctx)
 		if !ok {
 			return o, nil, false
 		}
@@ -693,9 +916,9 @@ func (b *bindIterator[I, O]) Next() (O, error, bool) {
 		}
 	}
 
-	v, err, ok := b.curr.Next()
+	v, err, ok := pull(ctx, b.curr)
 	if !ok {
-		err, ok := b.mapNext()
+		err, ok := b.mapNext(ctx)
 		if !ok {
 			return o, nil, false
 		}
@@ -719,6 +942,14 @@ func (b *bindIterator[I, O]) Reset() error {
 	return nil
 }
 
+func (b *bindIterator[I, O]) Close() error {
+	if b.curr == nil {
+		return Close(b.in)
+	}
+
+	return closeBoth(b.in, b.curr)
+}
+
 func CollectAndFold[I, O any](i Iterator[I], acc O, f func(I, O) (O, error)) ([]I, O, error) {
 	var collection []I
 	accResult, err := Fold(i, acc, func(v I, a O) (O, error) {
@@ -737,6 +968,7 @@ type clonedIterator[V any] struct {
 	base    Iterator[V]
 	cache   queue.Queue[V]
 	partner *clonedIterator[V]
+	closed  bool
 }
 
 func Clone[V any](i Iterator[V]) (Iterator[V], Iterator[V]) {
@@ -763,12 +995,25 @@ func (i *clonedIterator[V]) Reset() error {
 	return nil
 }
 
+func (i *clonedIterator[V]) Close() error {
+	i.closed = true
+	if !i.partner.closed {
+		return nil
+	}
+
+	return Close(i.base)
+}
+
 func (i *clonedIterator[V]) Next() (V, error, bool) {
+	return i.nextContext(context.Background())
+}
+
+func (i *clonedIterator[V]) nextContext(ctx context.Context) (V, error, bool) {
 	if v, ok := i.cache.Pop(); ok {
 		return v, nil, true
 	}
 
-	next, err, ok := i.base.Next()
+	next, err, ok := pull(ctx, i.base)
 	if err != nil {
 		var o V
 		return o, err, true
@@ -782,17 +1027,23 @@ func (i *clonedIterator[V]) Next() (V, error, bool) {
 	return next, nil, false
 }
 
+// Reverse walks bidirectional iterators backwards lazily, any other iterator is drained and
+// buffered first.
 func Reverse[V any](i Iterator[V]) (Iterator[V], error) {
-	s := stack.Stack[V]{}
-
-	if err := Each(i, func(v V) error {
-		s.Push(v)
+	if b, ok := i.(Bidirectional[V]); ok {
+		return &reversedIterator[V]{
+			in: b,
+		}, nil
+	}
 
-		return nil
-	}); err != nil {
+	els, err := Collect(i)
+	if err != nil {
 		return nil, err
 	}
 
-	return &s, nil
+	for l, r := 0, len(els)-1; l < r; l, r = l+1, r-1 {
+		els[l], els[r] = els[r], els[l]
+	}
 
+	return NewSliceIterator(els), nil
 }
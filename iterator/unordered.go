@@ -0,0 +1,174 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrorPolicy decides how parallel adapters react to errors
+type ErrorPolicy int
+
+const (
+	// FailFast stops at the first error, shutting down the workers
+	FailFast ErrorPolicy = iota
+	// CollectAll reports every error and carries on with the remaining values
+	CollectAll
+)
+
+type unorderedIterator[I, O any] struct {
+	workerPool
+	in      Iterator[I]
+	workers int
+	policy  ErrorPolicy
+	// f reports whether its output should be kept, allowing it to filter
+	f       func(I) (O, bool, error)
+	results chan result[O]
+}
+
+func newUnordered[I, O any](i Iterator[I], workers int, policy ErrorPolicy, f func(I) (O, bool, error)) *unorderedIterator[I, O] {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &unorderedIterator[I, O]{
+		in:      i,
+		workers: workers,
+		policy:  policy,
+		f:       f,
+	}
+}
+
+// ParallelMapUnordered applies f to the values of i on a pool of workers goroutines, producing each
+// result as soon as it is ready. Panics in f are reported as errors.
+func ParallelMapUnordered[I, O any](i Iterator[I], workers int, policy ErrorPolicy, f func(I) (O, error)) Iterator[O] {
+	return newUnordered(i, workers, policy, func(v I) (O, bool, error) {
+		o, err := f(v)
+		return o, true, err
+	})
+}
+
+// ParallelFilter checks the values of i on a pool of workers goroutines, producing those which
+// pass as soon as they are checked.
+func ParallelFilter[V any](i Iterator[V], workers int, policy ErrorPolicy, c func(V) (bool, error)) Iterator[V] {
+	return newUnordered(i, workers, policy, func(v V) (V, bool, error) {
+		ok, err := c(v)
+		return v, ok, err
+	})
+}
+
+// ParallelEach calls f with every value of i on a pool of workers goroutines. With CollectAll every
+// error is joined into the result, otherwise the first error is returned.
+func ParallelEach[V any](i Iterator[V], workers int, policy ErrorPolicy, f func(V) error) error {
+	p := newUnordered(i, workers, policy, func(v V) (struct{}, bool, error) {
+		return struct{}{}, false, f(v)
+	})
+	if policy == CollectAll {
+		_, err := CollectErrors[struct{}](p)
+		return err
+	}
+
+	return Each[struct{}](p, func(struct{}) error { return nil })
+}
+
+func (u *unorderedIterator[I, O]) start() {
+	u.results = make(chan result[O], u.workers)
+	jobs := make(chan I)
+
+	send := func(r result[O]) bool {
+		select {
+		case u.results <- r:
+			return true
+		case <-u.ctx.Done():
+			return false
+		}
+	}
+
+	work := func() {
+		for v := range jobs {
+			var o O
+			var keep bool
+			err := protect(func() (err error) {
+				o, keep, err = u.f(v)
+				return err
+			})
+			if (keep || err != nil) && !send(result[O]{v: o, err: err, ok: true}) {
+				return
+			}
+		}
+	}
+
+	feed := func() {
+		defer close(jobs)
+
+		for {
+			v, err, ok := pull(u.ctx, u.in)
+			if !ok {
+				return
+			}
+			if err != nil {
+				if !send(result[O]{err: err, ok: true}) {
+					return
+				}
+				continue
+			}
+
+			select {
+			case jobs <- v:
+			case <-u.ctx.Done():
+				return
+			}
+		}
+	}
+
+	u.workerPool.start(u.workers, feed, work, func() { close(u.results) })
+}
+
+func (u *unorderedIterator[I, O]) Next() (O, error, bool) {
+	return u.nextContext(context.Background())
+}
+
+func (u *unorderedIterator[I, O]) nextContext(ctx context.Context) (O, error, bool) {
+	var o O
+	if u.done {
+		return o, nil, false
+	}
+	if !u.started {
+		u.start()
+	}
+
+	var r result[O]
+	var ok bool
+	select {
+	case r, ok = <-u.results:
+	case <-ctx.Done():
+		return o, ctx.Err(), true
+	}
+	if !ok {
+		u.shutdown()
+		return o, nil, false
+	}
+	if r.err != nil && u.policy == FailFast {
+		u.shutdown()
+	}
+
+	return r.v, r.err, true
+}
+
+func (u *unorderedIterator[I, O]) Reset() error {
+	u.shutdown()
+	if err := u.in.Reset(); err != nil {
+		return err
+	}
+
+	u.done = false
+	return nil
+}
+
+func (u *unorderedIterator[I, O]) Close() error {
+	u.shutdown()
+	return Close(u.in)
+}
+
+func (u *unorderedIterator[I, O]) stage() (string, []any) {
+	return fmt.Sprintf("ParallelUnordered(%d)", u.workers), []any{u.in}
+}
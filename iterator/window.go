@@ -0,0 +1,146 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lucas-s-work/funcy-go/tuple"
+)
+
+// Partial selects which incomplete windows are emitted alongside the full ones
+type Partial int
+
+const (
+	NoPartial Partial = 0
+	// PartialStart emits the growing windows before the first full window
+	PartialStart Partial = 1
+	// PartialEnd emits the shrinking windows after the last full window
+	PartialEnd  Partial = 2
+	PartialBoth         = PartialStart | PartialEnd
+)
+
+type windowIterator[V any] struct {
+	in      Iterator[V]
+	size    int
+	step    int
+	partial Partial
+
+	// ring holds the last size values, the value at position p is at p % size
+	ring []V
+	pos  int
+	// The bounds of the last window emitted
+	lastStart, lastEnd int
+	full               bool
+	ending             bool
+	endStart           int
+}
+
+// Window yields overlapping windows of size values, starting a new window every step values. Each
+// window is a fresh slice so it can be kept by the caller.
+func Window[V any](i Iterator[V], size, step int) Iterator[[]V] {
+	return WindowPartial(i, size, step, NoPartial)
+}
+
+// WindowPartial yields windows like Window, also emitting the incomplete windows at the start
+// and/or end of i as chosen by partial.
+func WindowPartial[V any](i Iterator[V], size, step int, partial Partial) Iterator[[]V] {
+	if size < 1 {
+		size = 1
+	}
+	if step < 1 {
+		step = 1
+	}
+
+	return &windowIterator[V]{
+		in:      i,
+		size:    size,
+		step:    step,
+		partial: partial,
+		ring:    make([]V, size),
+	}
+}
+
+// Pairwise yields each pair of adjacent values
+func Pairwise[V any](i Iterator[V]) Iterator[tuple.Pair[V, V]] {
+	return Map(Window(i, 2, 1), func(w []V) (tuple.Pair[V, V], error) {
+		return tuple.NewPair(w[0], w[1]), nil
+	})
+}
+
+func (w *windowIterator[V]) window(start, end int) []V {
+	out := make([]V, end-start)
+	for p := start; p < end; p++ {
+		out[p-start] = w.ring[p%w.size]
+	}
+
+	w.lastStart, w.lastEnd = start, end
+	return out
+}
+
+func (w *windowIterator[V]) Next() ([]V, error, bool) {
+	return w.nextContext(context.Background())
+}
+
+func (w *windowIterator[V]) nextContext(ctx context.Context) ([]V, error, bool) {
+	for {
+		if w.ending {
+			if w.partial&PartialEnd == 0 || w.endStart >= w.pos {
+				return nil, nil, false
+			}
+
+			start := w.endStart
+			w.endStart += w.step
+			// A short stream can end on the last partial start window
+			if start == w.lastStart && w.pos == w.lastEnd {
+				continue
+			}
+
+			return w.window(start, w.pos), nil, true
+		}
+
+		v, err, ok := pull(ctx, w.in)
+		if !ok {
+			w.ending = true
+			w.endStart = 0
+			if w.full {
+				w.endStart = w.lastStart + w.step
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err, true
+		}
+
+		w.ring[w.pos%w.size] = v
+		w.pos++
+
+		end := w.pos
+		if end >= w.size && (end-w.size)%w.step == 0 {
+			w.full = true
+			return w.window(end-w.size, end), nil, true
+		}
+		if end < w.size && w.partial&PartialStart != 0 && (w.size-end)%w.step == 0 {
+			return w.window(0, end), nil, true
+		}
+	}
+}
+
+func (w *windowIterator[V]) Reset() error {
+	if err := w.in.Reset(); err != nil {
+		return err
+	}
+
+	w.pos = 0
+	w.lastStart, w.lastEnd = 0, 0
+	w.full = false
+	w.ending = false
+	return nil
+}
+
+func (w *windowIterator[V]) Close() error {
+	return Close(w.in)
+}
+
+func (w *windowIterator[V]) stage() (string, []any) {
+	return fmt.Sprintf("Window(%d, %d)", w.size, w.step), []any{w.in}
+}
@@ -0,0 +1,133 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucas-s-work/funcy-go/tuple"
+)
+
+// Zip pairs up the values of a and b, stopping when either is exhausted
+func Zip[A, B any](a Iterator[A], b Iterator[B]) Iterator[tuple.Pair[A, B]] {
+	return MergeMap(a, b, func(a A, b B) (tuple.Pair[A, B], error) {
+		return tuple.NewPair(a, b), nil
+	})
+}
+
+func Zip3[A, B, C any](a Iterator[A], b Iterator[B], c Iterator[C]) Iterator[tuple.Triple[A, B, C]] {
+	return MergeMap(Zip(a, b), c, func(p tuple.Pair[A, B], c C) (tuple.Triple[A, B, C], error) {
+		return tuple.NewTriple(p.First, p.Second, c), nil
+	})
+}
+
+type zipLongestIterator[A, B any] struct {
+	a            Iterator[A]
+	b            Iterator[B]
+	aDone, bDone bool
+}
+
+// ZipLongestOption pairs up the values of a and b until both are exhausted, the side which ran out
+// first is reported as absent.
+func ZipLongestOption[A, B any](a Iterator[A], b Iterator[B]) Iterator[tuple.Pair[tuple.Option[A], tuple.Option[B]]] {
+	return &zipLongestIterator[A, B]{
+		a: a,
+		b: b,
+	}
+}
+
+// ZipLongest pairs up the values of a and b until both are exhausted, using fillA and fillB in
+// place of the side which ran out first.
+func ZipLongest[A, B any](a Iterator[A], b Iterator[B], fillA A, fillB B) Iterator[tuple.Pair[A, B]] {
+	return Map(ZipLongestOption(a, b), func(p tuple.Pair[tuple.Option[A], tuple.Option[B]]) (tuple.Pair[A, B], error) {
+		return tuple.NewPair(p.First.Or(fillA), p.Second.Or(fillB)), nil
+	})
+}
+
+func nextOption[V any](ctx context.Context, i Iterator[V], done *bool) (tuple.Option[V], error) {
+	if *done {
+		return tuple.None[V](), nil
+	}
+
+	v, err, ok := pull(ctx, i)
+	if !ok {
+		*done = true
+		return tuple.None[V](), nil
+	}
+
+	return tuple.Some(v), err
+}
+
+func (z *zipLongestIterator[A, B]) Next() (tuple.Pair[tuple.Option[A], tuple.Option[B]], error, bool) {
+	return z.nextContext(context.Background())
+}
+
+func (z *zipLongestIterator[A, B]) nextContext(ctx context.Context) (tuple.Pair[tuple.Option[A], tuple.Option[B]], error, bool) {
+	var o tuple.Pair[tuple.Option[A], tuple.Option[B]]
+	// Both sides are pulled before an error is reported so they stay aligned
+	a, aErr := nextOption(ctx, z.a, &z.aDone)
+	b, bErr := nextOption(ctx, z.b, &z.bDone)
+	if err := errors.Join(aErr, bErr); err != nil {
+		return o, err, true
+	}
+	if z.aDone && z.bDone {
+		return o, nil, false
+	}
+
+	return tuple.NewPair(a, b), nil, true
+}
+
+func (z *zipLongestIterator[A, B]) Reset() error {
+	if err := z.a.Reset(); err != nil {
+		return err
+	}
+	if err := z.b.Reset(); err != nil {
+		return err
+	}
+
+	z.aDone, z.bDone = false, false
+	return nil
+}
+
+func (z *zipLongestIterator[A, B]) Close() error {
+	return closeBoth(z.a, z.b)
+}
+
+// Values are produced while either source has values
+func (z *zipLongestIterator[A, B]) SizeHint() (int, int, bool) {
+	min1, max1, exact1 := SizeHint(z.a)
+	min2, max2, exact2 := SizeHint(z.b)
+	if z.aDone {
+		min1, max1, exact1 = exactHint(0)
+	}
+	if z.bDone {
+		min2, max2, exact2 = exactHint(0)
+	}
+
+	min, max := min1, max1
+	if min2 > min {
+		min = min2
+	}
+	if max < 0 || max2 < 0 {
+		max = -1
+	} else if max2 > max {
+		max = max2
+	}
+
+	return min, max, (exact1 && exact2) || min == max
+}
+
+func (z *zipLongestIterator[A, B]) stage() (string, []any) {
+	return "ZipLongest", []any{z.a, z.b}
+}
+
+// Unzip splits an iterator of pairs into an iterator of each side. Values are buffered in the
+// style of Partition until the other side pulls them.
+func Unzip[A, B any](i Iterator[tuple.Pair[A, B]]) (Iterator[A], Iterator[B]) {
+	return newFork(i, func(p tuple.Pair[A, B], err error) (tuple.Option[A], tuple.Option[B], error) {
+		if err != nil {
+			return tuple.None[A](), tuple.None[B](), err
+		}
+
+		return tuple.Some(p.First), tuple.Some(p.Second), nil
+	}, "Unzip(first)", "Unzip(second)")
+}
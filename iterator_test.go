@@ -1,11 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
+	"maps"
 	"math"
+	"math/rand"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	. "github.com/lucas-s-work/funcy-go/iterator"
+	"github.com/lucas-s-work/funcy-go/metrics"
+	"github.com/lucas-s-work/funcy-go/queue"
+	"github.com/lucas-s-work/funcy-go/tuple"
 )
 
 func Expensive(f float64) (int, error) {
@@ -150,3 +165,1066 @@ func BenchmarkComplexFunctional(b *testing.B) {
 		})
 	}
 }
+
+func TestSeqRoundTrip(t *testing.T) {
+	i := FromSeq(slices.Values([]int{1, 2, 3, 4}))
+	i = Map(i, func(v int) (int, error) {
+		return v * 2, nil
+	})
+
+	var out []int
+	for v, err := range All(i) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, v)
+	}
+	if !slices.Equal(out, []int{2, 4, 6, 8}) {
+		t.Fatalf("unexpected values: %v", out)
+	}
+
+	// The sequence is restarted on reset
+	if err := i.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := Sum(i); s != 20 {
+		t.Fatalf("unexpected sum: %v", s)
+	}
+}
+
+func TestPullStop(t *testing.T) {
+	next, stop := iter.Pull(maps.Keys(map[string]int{"a": 1, "b": 2}))
+	i := FromPull(next, stop)
+
+	if _, _, ok := i.Next(); !ok {
+		t.Fatal("expected a value")
+	}
+	if err := Close(i); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := i.Next(); ok {
+		t.Fatal("expected no values after stopping")
+	}
+}
+
+func TestContextCancelsBlockedChannel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	c := make(chan int)
+	err := EachContext(ctx, NewChanIterator(c), func(int) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got: %v", err)
+	}
+
+	// Adapters between the context and the channel must not hide the pending receive
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	i := Filter(Map(NewChanIterator(c), func(v int) (int, error) { return v * 2, nil }), func(int) (bool, error) { return true, nil })
+	go func() { c <- 1 }()
+	err = EachContext(ctx, WithLimit(Skip(i, 1), 10), func(int) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error through adapters, got: %v", err)
+	}
+}
+
+func TestContextCancelsThroughAdapters(t *testing.T) {
+	id := func(v int) (int, error) { return v, nil }
+	never := func() *manualClock { return &manualClock{waiting: make(chan struct{}, 8)} }
+
+	cases := map[string]func(idle Iterator[int]) Iterator[int]{
+		// Combining several sources
+		"Merge": func(idle Iterator[int]) Iterator[int] { return Merge(idle, nums(2)) },
+		"MergeMap": func(idle Iterator[int]) Iterator[int] {
+			return MergeMap(idle, nums(2), func(a, b int) (int, error) { return a + b, nil })
+		},
+		"Concat":      func(idle Iterator[int]) Iterator[int] { return Concat(nums(2), idle) },
+		"Interleave":  func(idle Iterator[int]) Iterator[int] { return Interleave(SkipExhausted, nums(2), idle) },
+		"MergeSorted": func(idle Iterator[int]) Iterator[int] { return MergeSortedOrdered(nums(2), idle) },
+		"ZipLongest": func(idle Iterator[int]) Iterator[int] {
+			return Map(ZipLongest(idle, nums(2), 0, 0), func(p tuple.Pair[int, int]) (int, error) { return p.First, nil })
+		},
+
+		// Sharing a source between several iterators
+		"Partition": func(idle Iterator[int]) Iterator[int] {
+			l, _ := Partition(idle, func(int) bool { return true })
+			return l
+		},
+		"Clone": func(idle Iterator[int]) Iterator[int] {
+			l, _ := Clone(idle)
+			return l
+		},
+		"DeadLetter": func(idle Iterator[int]) Iterator[int] {
+			l, _ := DeadLetter(idle)
+			return l
+		},
+		"Unzip": func(idle Iterator[int]) Iterator[int] {
+			l, _ := Unzip(Zip(idle, nums(2)))
+			return l
+		},
+		"Synchronized": func(idle Iterator[int]) Iterator[int] { return Synchronized(idle) },
+
+		// Buffering or expanding values
+		"Bind": func(idle Iterator[int]) Iterator[int] {
+			return Bind(nums(1), func(int) (Iterator[int], error) { return idle, nil })
+		},
+		"Duplicate":  func(idle Iterator[int]) Iterator[int] { return Duplicate(idle, 2) },
+		"Sort":       func(idle Iterator[int]) Iterator[int] { return Sort(idle) },
+		"Peekable":   func(idle Iterator[int]) Iterator[int] { return Peekable(idle) },
+		"SkipErrors": func(idle Iterator[int]) Iterator[int] { return SkipErrors(idle, nil) },
+		"Window": func(idle Iterator[int]) Iterator[int] {
+			return Map(Window(idle, 2, 1), func(w []int) (int, error) { return w[0], nil })
+		},
+		"WithContext": func(idle Iterator[int]) Iterator[int] { return WithContext(context.Background(), idle) },
+
+		// Waiting on a clock or on background goroutines
+		"Throttle":     func(idle Iterator[int]) Iterator[int] { return Throttle(idle, 1, 1) },
+		"ThrottleWait": func(Iterator[int]) Iterator[int] { return ThrottleWithClock(nums(5), 1, 1, never()) },
+		"MinInterval":  func(Iterator[int]) Iterator[int] { return MinIntervalWithClock(nums(5), time.Hour, never()) },
+		"ParallelMap":  func(idle Iterator[int]) Iterator[int] { return ParallelMap(idle, 2, id) },
+		"Unordered":    func(idle Iterator[int]) Iterator[int] { return ParallelMapUnordered(idle, 2, FailFast, id) },
+		"Prefetch":     func(idle Iterator[int]) Iterator[int] { return Prefetch(idle, 2) },
+	}
+	for name, build := range cases {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		err := EachContext(ctx, build(NewChanIterator(make(chan int))), func(int) error { return nil })
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s: expected deadline error, got: %v", name, err)
+		}
+	}
+}
+
+func TestContextCancelsGenerator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := Map(NewNaturalGenerator(), func(v int) (int, error) {
+		if v == 100 {
+			cancel()
+		}
+
+		return v, nil
+	})
+
+	_, err := FoldContext(ctx, n, 0, func(v, acc int) (int, error) { return acc + v, nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got: %v", err)
+	}
+}
+
+type closeCounter struct {
+	io.Reader
+	closes int
+}
+
+func (c *closeCounter) Close() error {
+	c.closes++
+	return nil
+}
+
+func TestClosePropagatesOnEarlyStop(t *testing.T) {
+	r := &closeCounter{Reader: strings.NewReader("abcdefgh")}
+	i := Map(NewReaderIterator(r, 2), func(b []byte) (string, error) {
+		return string(b), nil
+	})
+	i = Filter(i, func(s string) (bool, error) {
+		return s != "cd", nil
+	})
+
+	v, err, ok := First(WithLimit(i, 3), func(s string) (bool, error) {
+		return s == "ef", nil
+	})
+	if err != nil || !ok || v != "ef" {
+		t.Fatalf("unexpected result: %v %v %v", v, err, ok)
+	}
+	if r.closes != 1 {
+		t.Fatalf("expected reader to be closed once, closed %v times", r.closes)
+	}
+}
+
+func TestPartitionClosesSourceOnce(t *testing.T) {
+	r := &closeCounter{Reader: strings.NewReader("abcd")}
+	a, b := Partition(NewReaderIterator(r, 1), func(b []byte) bool {
+		return b[0]%2 == 0
+	})
+
+	Close(a)
+	if r.closes != 0 {
+		t.Fatal("source closed while partner still open")
+	}
+	Close(b)
+	if r.closes != 1 {
+		t.Fatalf("expected source to be closed once, closed %v times", r.closes)
+	}
+}
+
+func TestPeekable(t *testing.T) {
+	p := Peekable(NewStringIterator("abc"))
+
+	if v, _, _ := p.Peek(); v != "a" {
+		t.Fatalf("unexpected peek: %v", v)
+	}
+	vs, err := p.PeekN(5)
+	if err != nil || !slices.Equal(vs, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected lookahead: %v %v", vs, err)
+	}
+
+	v, _, _ := p.Next()
+	p.Unread("z")
+	p.Unread(v)
+	out, _ := Collect[string](p)
+	if strings.Join(out, "") != "azbc" {
+		t.Fatalf("unexpected values: %v", out)
+	}
+
+	p.Unread("z")
+	p.Reset()
+	if v, _, _ := p.Next(); v != "a" {
+		t.Fatalf("lookahead not cleared on reset: %v", v)
+	}
+}
+
+func TestSizeHint(t *testing.T) {
+	s := NewSliceIterator([]int{1, 2, 3, 4, 5, 6, 7})
+	m := Map(s, func(v int) (int, error) { return v, nil })
+
+	cases := []struct {
+		name     string
+		i        Iterator[int]
+		min, max int
+		exact    bool
+	}{
+		{"map", m, 7, 7, true},
+		{"limit", WithLimit(m, 3), 3, 3, true},
+		{"filter", Filter(m, func(int) (bool, error) { return true, nil }), 0, 7, false},
+		{"duplicate", Duplicate(m, 2), 14, 14, true},
+		{"matrix", NewMatrixIterator([][]int{{1, 2}, {}, {3}}), 3, 3, true},
+		{"generator", WithLimit(NewNaturalGenerator(), 4), 0, 4, false},
+	}
+	for _, c := range cases {
+		min, max, exact := SizeHint(c.i)
+		if min != c.min || max != c.max || exact != c.exact {
+			t.Errorf("%s: unexpected hint (%v, %v, %v)", c.name, min, max, exact)
+		}
+	}
+
+	if min, max, _ := SizeHint(Cons(m, 3)); min != 3 || max != 3 {
+		t.Errorf("cons: unexpected hint (%v, %v)", min, max)
+	}
+	if chunks, err := Collect(Cons(NewSliceIterator([]int{1, 2, 3}), math.MaxInt)); err != nil || len(chunks) != 1 {
+		t.Errorf("cons: unexpected chunks with an unbounded stride: %v %v", chunks, err)
+	}
+
+	out, _ := Collect(NewMatrixIterator([][]int{{1, 2}, {}, {3}}))
+	if !slices.Equal(out, []int{1, 2, 3}) || cap(out) != 3 {
+		t.Fatalf("unexpected collection: %v with capacity %v", out, cap(out))
+	}
+}
+
+func TestSeekThroughMap(t *testing.T) {
+	calls := 0
+	m := Map(NewSliceIterator([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}), func(v int) (int, error) {
+		calls++
+		return v * 10, nil
+	})
+
+	m = Skip(m, 3)
+	if v, _, _ := m.Next(); v != 30 {
+		t.Fatalf("unexpected value after skip: %v", v)
+	}
+	if v, _, _ := Nth(WithLimit(m, 4), 2); v != 60 {
+		t.Fatalf("unexpected nth value: %v", v)
+	}
+	if _, _, ok := Nth(WithLimit(m, 2), 2); ok {
+		t.Fatal("expected nth past the limit to be empty")
+	}
+	if calls != 2 {
+		t.Fatalf("expected only fetched values to be mapped, mapped %v", calls)
+	}
+
+	if v, _, _ := Nth(NewMatrixIterator([][]int{{1, 2}, {}, {3, 4}}), 2); v != 3 {
+		t.Fatalf("unexpected matrix value: %v", v)
+	}
+
+	sl := NewSliceIterator([]int{1, 2, 3})
+	sl.Next()
+	if _, _, ok := Nth(sl, -1); ok {
+		t.Fatal("expected no value for a negative index on a seeker")
+	}
+	if _, _, ok := Nth(nums(3), -1); ok {
+		t.Fatal("expected no value for a negative index")
+	}
+	if v, _, _ := sl.Next(); v != 2 {
+		t.Fatalf("expected a negative index to leave the iterator untouched, got %v", v)
+	}
+}
+
+func TestLazyReverse(t *testing.T) {
+	calls := 0
+	m := Map(NewSliceIterator([]int{1, 2, 3, 4}), func(v int) (int, error) {
+		calls++
+		return v * 2, nil
+	})
+	m.Next()
+
+	r, err := Reverse(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := Collect(r)
+	if !slices.Equal(out, []int{8, 6, 4}) {
+		t.Fatalf("unexpected values: %v", out)
+	}
+	if calls != 4 {
+		t.Fatalf("expected each value to be mapped once, mapped %v", calls)
+	}
+
+	r, _ = Reverse(NewMatrixIterator([][]int{{1, 2}, {}, {3}}))
+	if out, _ := Collect(r); !slices.Equal(out, []int{3, 2, 1}) {
+		t.Fatalf("unexpected matrix values: %v", out)
+	}
+}
+
+// funcSource is a comparable type whose values can still hold an uncomparable function
+type funcSource struct {
+	next any
+}
+
+func (f funcSource) Next() (int, error, bool) {
+	return f.next.(func() (int, error, bool))()
+}
+
+func (f funcSource) Reset() error {
+	return nil
+}
+
+func parseInts(ss ...string) Iterator[int] {
+	return Map(NewSliceIterator(ss), strconv.Atoi)
+}
+
+// nums yields the naturals below n
+func nums(n int) Iterator[int] {
+	return WithLimit(NewNaturalGenerator(), n)
+}
+
+// collect renders the values of i for comparison, or the error which stopped it
+func collect[V any](i Iterator[V]) string {
+	vs, err := Collect(i)
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprint(vs)
+}
+
+func TestErrorPolicies(t *testing.T) {
+	vs, err := CollectErrors(parseInts("1", "a", "2", "b"))
+	if !slices.Equal(vs, []int{1, 2}) {
+		t.Fatalf("unexpected values: %v", vs)
+	}
+	if err == nil || len(err.(interface{ Unwrap() []error }).Unwrap()) != 2 {
+		t.Fatalf("expected two joined errors, got: %v", err)
+	}
+
+	vs, err = Collect(StopAfterNErrors(parseInts("1", "a", "2", "b", "3"), 2))
+	if err == nil || vs != nil {
+		t.Fatalf("expected to stop on the second error, got: %v %v", vs, err)
+	}
+
+	passed, failed := DeadLetter(parseInts("1", "a", "2", "b"))
+	fs, _ := Collect(failed)
+	vs, _ = Collect(passed)
+	if !slices.Equal(vs, []int{1, 2}) || len(fs) != 2 || fs[0].Err == nil {
+		t.Fatalf("unexpected split: %v %v", vs, fs)
+	}
+}
+
+func TestCheckpointRestore(t *testing.T) {
+	build := func() Iterator[int] {
+		p := Filter(NewPrimeGenerator(), func(v int) (bool, error) { return v%4 == 1, nil })
+		return Scan(WithLimit(p, 8), 0, func(v, acc int) (int, error) { return acc + v, nil })
+	}
+
+	i := build()
+	CollectWithLimit(i, 3)
+	state, err := Checkpoint(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, _ := Collect(i)
+
+	resumed := build()
+	if err := Restore(resumed, state); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := Collect(resumed)
+	if !slices.Equal(out, rest) || len(out) != 5 {
+		t.Fatalf("resumed pipeline diverged: %v != %v", out, rest)
+	}
+
+	if _, err := Checkpoint(NewRandomIntGenerator()); err == nil {
+		t.Fatal("expected generating functions not to be checkpointable")
+	}
+}
+
+func TestDescribeAndTrace(t *testing.T) {
+	evens, odds := Partition(NewSliceIterator([]int{1, 2, 3, 4}), func(v int) bool { return v%2 == 0 })
+	i := Named("squares", Map(evens, func(v int) (int, error) { return v * v, nil }))
+	i = Merge(i, Named("odds", odds))
+
+	want := "Merge\n  Named(squares)\n    Map\n      Partition\n        SliceIterator[int]\n  Named(odds)\n    Partition\n      SliceIterator[int]\n"
+	if d := Describe(i); d != want {
+		t.Fatalf("unexpected description:\n%s", d)
+	}
+	if dot := DescribeDOT(i); strings.Count(dot, "SliceIterator") != 1 {
+		t.Fatalf("expected shared source to be a single node:\n%s", dot)
+	}
+
+	// A comparable type holding an uncomparable value must not be used as a map key
+	if dot := DescribeDOT(WithLimit[int](funcSource{next: NewNaturalGenerator().Next}, 3)); !strings.Contains(dot, "funcSource") {
+		t.Fatalf("expected the source to be described:\n%s", dot)
+	}
+
+	var lines []string
+	Collect(Trace(i, func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}))
+	if lines[0] != "squares: 4" || lines[1] != "odds: 1" {
+		t.Fatalf("unexpected trace: %v", lines)
+	}
+}
+
+func TestInstrumentedMetrics(t *testing.T) {
+	c := metrics.NewCollector()
+	i := Named("evens", Filter(NewSliceIterator([]int{1, 2, 3, 4, 5}), func(v int) (bool, error) {
+		return v%2 == 0, nil
+	}))
+	i = Map(i, func(v int) (int, error) {
+		if v == 4 {
+			return 0, fmt.Errorf("four")
+		}
+
+		return v, nil
+	})
+
+	if _, err := Fold(Instrument[int](c, i), 0, func(v, acc int) (int, error) { return acc + v, nil }); err == nil {
+		t.Fatal("expected the mapping error")
+	}
+
+	stages := c.Stages()
+	if s := stages["evens"]; s.Elements != 2 || s.Drops != 2 {
+		t.Fatalf("unexpected filter metrics: %+v", s)
+	}
+	if s := stages["Map"]; s.Elements != 1 || s.Errors != 1 {
+		t.Fatalf("unexpected map metrics: %+v", s)
+	}
+	if s := stages["Fold"]; s.Elements != 1 {
+		t.Fatalf("unexpected fold metrics: %+v", s)
+	}
+
+	var b strings.Builder
+	c.WritePrometheus(&b)
+	for _, line := range []string{
+		`funcy_stage_dropped_total{stage="evens"} 2`,
+		`funcy_stage_latency_seconds_count{stage="Map"} 1`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, b.String())
+		}
+	}
+	// Wrapping the instrumented pipeline, as FoldContext does, must still report the Fold
+	c = metrics.NewCollector()
+	FoldContext(context.Background(), Instrument[int](c, NewSliceIterator([]int{1, 2, 3})), 0, func(v, acc int) (int, error) { return acc + v, nil })
+	if s := c.Stages()["Fold"]; s.Elements != 3 {
+		t.Fatalf("unexpected fold metrics through a context: %+v", s)
+	}
+}
+
+// checkNoLeaks waits for the goroutine count to drop back to before, goroutines which have already
+// signalled their shutdown can still take a moment to exit
+func checkNoLeaks(t *testing.T, before int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for after := runtime.NumGoroutine(); after > before; after = runtime.NumGoroutine() {
+		if time.Now().After(deadline) {
+			t.Fatalf("leaked %v goroutines", after-before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestParallelMapOrdered(t *testing.T) {
+	before := runtime.NumGoroutine()
+	p := ParallelMap(WithLimit(NewNaturalGenerator(), 200), 8, func(v int) (int, error) {
+		time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+		return v * 2, nil
+	})
+
+	out, err := Collect(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range out {
+		if v != i*2 {
+			t.Fatalf("out of order value %v at %v", v, i)
+		}
+	}
+
+	// Stopping early must still shut the workers down
+	p.Reset()
+	if v, _, _ := First(p, func(v int) (bool, error) { return v == 20, nil }); v != 20 {
+		t.Fatalf("unexpected value: %v", v)
+	}
+	_, err = Collect(ParallelMap(NewNaturalGenerator(), 4, func(v int) (int, error) {
+		if v == 50 {
+			return 0, fmt.Errorf("fifty")
+		}
+
+		return v, nil
+	}))
+	if err == nil {
+		t.Fatal("expected the mapping error")
+	}
+	checkNoLeaks(t, before)
+}
+
+func TestParallelUnordered(t *testing.T) {
+	out, err := Collect(ParallelFilter(WithLimit(NewNaturalGenerator(), 100), 4, FailFast, func(v int) (bool, error) {
+		return v%2 == 0, nil
+	}))
+	if err != nil || len(out) != 50 {
+		t.Fatalf("unexpected filter result: %v %v", len(out), err)
+	}
+	if s, _ := Sum(NewSliceIterator(out)); s != 2450 {
+		t.Fatalf("unexpected sum: %v", s)
+	}
+
+	var mu sync.Mutex
+	seen := 0
+	err = ParallelEach(WithLimit(NewNaturalGenerator(), 10), 3, CollectAll, func(v int) error {
+		if v%5 == 0 {
+			panic("multiple of five")
+		}
+
+		mu.Lock()
+		seen++
+		mu.Unlock()
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "worker panic: multiple of five") {
+		t.Fatalf("expected panics to be reported, got: %v", err)
+	}
+	if seen != 8 || len(err.(interface{ Unwrap() []error }).Unwrap()) != 2 {
+		t.Fatalf("expected every value to be processed, saw %v", seen)
+	}
+
+	_, err = Collect(ParallelMapUnordered(NewNaturalGenerator(), 4, FailFast, func(v int) (int, error) {
+		if v == 30 {
+			return 0, fmt.Errorf("thirty")
+		}
+
+		return v, nil
+	}))
+	if err == nil {
+		t.Fatal("expected the mapping error")
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	before := runtime.NumGoroutine()
+	p := Prefetch(Map(NewSliceIterator([]int{1, 2, 3, 4, 5}), func(v int) (int, error) {
+		if v == 3 {
+			return 0, fmt.Errorf("three")
+		}
+
+		return v, nil
+	}), 2)
+
+	vs, err := CollectErrors(p)
+	if !slices.Equal(vs, []int{1, 2, 4, 5}) || err == nil {
+		t.Fatalf("unexpected values: %v %v", vs, err)
+	}
+
+	if err := p.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _, _ := p.Next(); v != 1 {
+		t.Fatalf("unexpected value after reset: %v", v)
+	}
+
+	// Stopping early on an infinite source must not leak the producer
+	CollectWithLimit(Prefetch(NewNaturalGenerator(), 4), 10)
+	Close(p)
+
+	// Stopping early while the producer is blocked on an idle channel must interrupt the receive
+	c := make(chan int, 1)
+	c <- 1
+	err = Each(Prefetch(NewChanIterator(c), 2), func(int) error { return fmt.Errorf("stop") })
+	if err == nil || err.Error() != "stop" {
+		t.Fatalf("expected the early error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := EachContext(ctx, Prefetch(NewChanIterator(c), 2), func(int) error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got: %v", err)
+	}
+	checkNoLeaks(t, before)
+}
+
+// Run with -race
+func TestWorkSource(t *testing.T) {
+	q := &queue.Queue[int]{}
+	for i := 0; i < 1000; i++ {
+		q.Push(i)
+	}
+
+	next := WorkSource[int](q)
+	totals := make([]int, 8)
+	var wg sync.WaitGroup
+	for w := range totals {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v, _, ok := next(); ok; v, _, ok = next() {
+				totals[w] += v
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s, _ := Sum(NewSliceIterator(totals)); s != 499500 {
+		t.Fatalf("values lost or duplicated, sum: %v", s)
+	}
+
+	// Each Count closes its side when done, which must not close the source for the others
+	source := FromSeq(slices.Values(make([]int, 100)))
+	shared := Synchronized(source)
+	counts := make([]int, 4)
+	for w := range counts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counts[w], _ = Count(WithLimit(shared, 25))
+		}()
+	}
+	wg.Wait()
+
+	if s, _ := Sum(NewSliceIterator(counts)); s != 100 {
+		t.Fatalf("unexpected number of values pulled: %v", counts)
+	}
+	if err := Close(source); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBroadcastFanIn(t *testing.T) {
+	ctx := context.Background()
+	outs, errs := Broadcast(ctx, WithLimit(NewNaturalGenerator(), 100), 3, 4, Block)
+
+	total, err := Sum(FanIn(outs...))
+	if err != nil || total != 3*4950 {
+		t.Fatalf("unexpected total: %v %v", total, err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c, errs := ToChan(ctx, NewNaturalGenerator(), 0)
+	<-c
+	cancel()
+	for range c {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got: %v", err)
+	}
+	nils := make(chan error, 2)
+	nils <- nil
+	nils <- io.EOF
+	close(nils)
+	if vs, err := Collect(FanIn[error](nils)); err != nil || len(vs) != 2 || vs[0] != nil || vs[1] != io.EOF {
+		t.Fatalf("unexpected values: %v %v", vs, err)
+	}
+}
+
+// fakeClock advances time by however long it is asked to wait
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.now = f.now.Add(d)
+	c := make(chan time.Time, 1)
+	c <- f.now
+	return c
+}
+
+func TestThrottle(t *testing.T) {
+	clock := &fakeClock{}
+	var times []time.Duration
+	i := ThrottleWithClock(NewNaturalGenerator(), 10, 3, clock)
+	Each(WithLimit(i, 6), func(int) error {
+		times = append(times, clock.now.Sub(time.Time{}))
+		return nil
+	})
+
+	ms := time.Millisecond
+	if !slices.Equal(times, []time.Duration{0, 0, 0, 100 * ms, 200 * ms, 300 * ms}) {
+		t.Fatalf("unexpected emission times: %v", times)
+	}
+
+	clock = &fakeClock{}
+	times = nil
+	Each(WithLimit(MinIntervalWithClock(NewNaturalGenerator(), 50*ms, clock), 3), func(int) error {
+		times = append(times, clock.now.Sub(time.Time{}))
+		clock.now = clock.now.Add(20 * ms)
+		return nil
+	})
+	if !slices.Equal(times, []time.Duration{0, 50 * ms, 100 * ms}) {
+		t.Fatalf("unexpected emission times: %v", times)
+	}
+
+	if _, err := Collect(Throttle(NewNaturalGenerator(), 0, 1)); err == nil {
+		t.Fatal("expected a non-positive rate to be rejected")
+	}
+}
+
+// manualClock only fires timers once the test advances it, signalling on waiting when one is set
+type manualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []chan time.Time
+	waiting chan struct{}
+}
+
+func (m *manualClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.now
+}
+
+func (m *manualClock) After(time.Duration) <-chan time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := make(chan time.Time, 1)
+	m.timers = append(m.timers, c)
+	m.waiting <- struct{}{}
+	return c
+}
+
+func (m *manualClock) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.now = m.now.Add(d)
+	for _, c := range m.timers {
+		c <- m.now
+	}
+	m.timers = nil
+}
+
+func TestBatch(t *testing.T) {
+	clock := &manualClock{waiting: make(chan struct{}, 8)}
+	c := make(chan int)
+	b := BatchWithClock(NewChanIterator(c), 3, time.Second, clock)
+
+	batches := make(chan []int)
+	go func() {
+		defer close(batches)
+		Each(b, func(vs []int) error {
+			batches <- vs
+			return nil
+		})
+	}()
+
+	c <- 1
+	c <- 2
+	c <- 3
+	if vs := <-batches; !slices.Equal(vs, []int{1, 2, 3}) {
+		t.Fatalf("expected a full batch, got: %v", vs)
+	}
+	<-clock.waiting
+
+	// A slow source is flushed once the wait elapses
+	c <- 4
+	<-clock.waiting
+	clock.Advance(time.Second)
+	if vs := <-batches; !slices.Equal(vs, []int{4}) {
+		t.Fatalf("expected a partial batch, got: %v", vs)
+	}
+
+	c <- 5
+	close(c)
+	if vs := <-batches; !slices.Equal(vs, []int{5}) {
+		t.Fatalf("expected the remainder on close, got: %v", vs)
+	}
+	if _, ok := <-batches; ok {
+		t.Fatal("expected no more batches")
+	}
+	if vs, err := Collect(Batch(nums(3), math.MaxInt, time.Hour)); err != nil || len(vs) != 1 || len(vs[0]) != 3 {
+		t.Fatalf("unexpected batches with an unbounded size: %v %v", vs, err)
+	}
+
+	// An early error while the channel is idle must not leave Close waiting on the receive
+	idle := make(chan int, 1)
+	idle <- 1
+	err := Each(Batch(NewChanIterator(idle), 1, time.Second), func([]int) error { return fmt.Errorf("stop") })
+	if err == nil || err.Error() != "stop" {
+		t.Fatalf("expected the early error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := EachContext(ctx, Batch(NewChanIterator(idle), 2, time.Hour), func([]int) error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got: %v", err)
+	}
+}
+
+func TestParallelFold(t *testing.T) {
+	if s, err := ParallelSum(WithLimit(NewNaturalGenerator(), 1000), 4); err != nil || s != 499500 {
+		t.Fatalf("unexpected sum: %v %v", s, err)
+	}
+	if c, _ := ParallelCountFilter(WithLimit(NewNaturalGenerator(), 1000), 4, func(v int) bool { return v%3 == 0 }); c != 334 {
+		t.Fatalf("unexpected count: %v", c)
+	}
+
+	groups, err := ParallelGroupBy(WithLimit(NewNaturalGenerator(), 100), 4, func(v int) int { return v % 10 })
+	if err != nil || len(groups) != 10 || len(groups[3]) != 10 {
+		t.Fatalf("unexpected groups: %v %v", groups, err)
+	}
+
+	_, err = ParallelFold(NewNaturalGenerator(), 4, func() int { return 0 }, func(v, acc int) (int, error) {
+		if v == 500 {
+			return 0, fmt.Errorf("five hundred")
+		}
+
+		return acc + v, nil
+	}, func(a, b int) (int, error) { return a + b, nil })
+	if err == nil {
+		t.Fatal("expected the step error to stop an infinite fold")
+	}
+}
+
+func TestConcurrentQueue(t *testing.T) {
+	q := queue.NewConcurrent[int](2)
+	go func() {
+		for i := 0; i < 100; i++ {
+			if err := q.Push(i); err != nil {
+				panic(err)
+			}
+		}
+		q.Close()
+	}()
+
+	counts := make([]int, 4)
+	var wg sync.WaitGroup
+	for w := range counts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counts[w], _ = Sum[int](q)
+		}()
+	}
+	wg.Wait()
+
+	if s, _ := Sum(NewSliceIterator(counts)); s != 4950 {
+		t.Fatalf("values lost or duplicated, sum: %v", s)
+	}
+	if err := q.Push(1); !errors.Is(err, queue.ErrClosed) {
+		t.Fatalf("expected push after close to fail, got: %v", err)
+	}
+
+	q = queue.NewConcurrent[int](0)
+	if _, ok := q.PopTimeout(time.Millisecond); ok {
+		t.Fatal("expected pop from an empty queue to time out")
+	}
+	q.Push(1)
+	if v, ok := q.TryPop(); !ok || v != 1 {
+		t.Fatalf("unexpected value: %v %v", v, ok)
+	}
+}
+
+func TestZipUnzip(t *testing.T) {
+	words := NewSliceIterator([]string{"a", "b", "c"})
+	z, _ := Collect(ZipLongest(NewSliceIterator([]int{1, 2}), words, -1, ""))
+	if len(z) != 3 || z[2] != tuple.NewPair(-1, "c") {
+		t.Fatalf("unexpected pairs: %v", z)
+	}
+
+	// An error on one side must not shift the pairs after it
+	z, err := CollectErrors(ZipLongest(parseInts("1", "x", "3"), NewStringIterator("abcd"), -1, "-"))
+	want := []tuple.Pair[int, string]{tuple.NewPair(1, "a"), tuple.NewPair(3, "c"), tuple.NewPair(-1, "d")}
+	if err == nil || !slices.Equal(z, want) {
+		t.Fatalf("unexpected pairs after an error: %v %v", z, err)
+	}
+
+	words.Reset()
+	z3, _ := Collect(Zip3(NewNaturalGenerator(), words, NewStringIterator("xyz")))
+	if len(z3) != 3 || z3[1] != tuple.NewTriple(1, "b", "y") {
+		t.Fatalf("unexpected triples: %v", z3)
+	}
+
+	words.Reset()
+	ns, ws := Unzip(Zip(NewNaturalGenerator(), words))
+	second, _ := Collect(ws)
+	first, _ := Collect(ns)
+	if !slices.Equal(first, []int{0, 1, 2}) || !slices.Equal(second, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected sides: %v %v", first, second)
+	}
+}
+
+func TestConcatInterleave(t *testing.T) {
+	c := Concat(NewSliceIterator([]int{1, 2}), NewSliceIterator([]int{}), NewSliceIterator([]int{3}))
+	out, _ := Collect(c)
+	c.Reset()
+	again, _ := Collect(c)
+	if !slices.Equal(out, []int{1, 2, 3}) || !slices.Equal(again, out) {
+		t.Fatalf("unexpected values: %v then %v", out, again)
+	}
+
+	sources := func() []Iterator[string] {
+		return []Iterator[string]{NewStringIterator("ab"), NewStringIterator("cdef"), NewStringIterator("g")}
+	}
+	out2, _ := Collect(Interleave(SkipExhausted, sources()...))
+	if strings.Join(out2, "") != "acgbdef" {
+		t.Fatalf("unexpected interleaving: %v", out2)
+	}
+	out2, _ = Collect(Interleave(StopOnExhausted, sources()...))
+	if strings.Join(out2, "") != "acgbd" {
+		t.Fatalf("unexpected interleaving: %v", out2)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	shards := func() []Iterator[int] {
+		return []Iterator[int]{
+			NewSliceIterator([]int{1, 4, 7, 10}),
+			NewSliceIterator([]int{2, 4, 8}),
+			NewSliceIterator([]int{}),
+			NewSliceIterator([]int{0, 4, 11}),
+		}
+	}
+
+	out, _ := Collect(MergeSortedOrdered(shards()...))
+	if !slices.Equal(out, []int{0, 1, 2, 4, 4, 4, 7, 8, 10, 11}) {
+		t.Fatalf("unexpected merge: %v", out)
+	}
+
+	m := MergeSortedUnique(func(a, b int) bool { return a < b }, shards()...)
+	out, _ = Collect(m)
+	m.Reset()
+	again, _ := Collect(m)
+	if !slices.Equal(out, []int{0, 1, 2, 4, 7, 8, 10, 11}) || !slices.Equal(again, out) {
+		t.Fatalf("unexpected unique merge: %v then %v", out, again)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	cases := []struct {
+		i    Iterator[[]int]
+		want string
+	}{
+		{Window(nums(5), 3, 1), "[[0 1 2] [1 2 3] [2 3 4]]"},
+		{Window(nums(7), 2, 3), "[[0 1] [3 4]]"},
+		{WindowPartial(nums(5), 3, 1, PartialBoth), "[[0] [0 1] [0 1 2] [1 2 3] [2 3 4] [3 4] [4]]"},
+		{WindowPartial(nums(6), 4, 2, PartialEnd), "[[0 1 2 3] [2 3 4 5] [4 5]]"},
+		{WindowPartial(nums(2), 3, 1, PartialBoth), "[[0] [0 1] [1]]"},
+	}
+	for _, c := range cases {
+		if got := collect(c.i); got != c.want {
+			t.Errorf("expected %v, got %v", c.want, got)
+		}
+	}
+
+	pairs, _ := Collect(Pairwise(NewStringIterator("abc")))
+	if len(pairs) != 2 || pairs[1] != tuple.NewPair("b", "c") {
+		t.Fatalf("unexpected pairs: %v", pairs)
+	}
+}
+
+func TestSlicing(t *testing.T) {
+	less := func(n int) func(int) (bool, error) {
+		return func(v int) (bool, error) {
+			return v < n, nil
+		}
+	}
+
+	cases := []struct {
+		i    Iterator[int]
+		want string
+	}{
+		{TakeWhile(nums(10), less(3)), "[0 1 2]"},
+		{TakeUntil(nums(10), func(v int) (bool, error) { return v == 3, nil }), "[0 1 2 3]"},
+		{DropWhile(nums(6), less(3)), "[3 4 5]"},
+		{Skip(nums(6), 4), "[4 5]"},
+		{Skip(NewSliceIterator([]int{1, 2, 3}), 5), "[]"},
+		{StepBy(nums(10), 3), "[0 3 6 9]"},
+		{StepBy(NewSliceIterator([]int{0, 1, 2, 3, 4, 5, 6}), 3), "[0 3 6]"},
+		{TakeWhile(nums(3), func(int) (bool, error) { return false, fmt.Errorf("boom") }), "boom"},
+	}
+	for _, c := range cases {
+		if got := collect(c.i); got != c.want {
+			t.Errorf("expected %v, got %v", c.want, got)
+		}
+	}
+
+	// Skipping is deferred until the first value is pulled and happens again after a Reset
+	calls := 0
+	s := Skip(Map(NewSliceIterator([]int{1, 2, 3, 4}), func(v int) (int, error) {
+		calls++
+		return v, nil
+	}), 2)
+	if calls != 0 {
+		t.Fatalf("skip should be lazy, map was called %v times", calls)
+	}
+	if got := collect(s); got != "[3 4]" || calls != 2 {
+		t.Fatalf("unexpected skip result %v after %v calls", got, calls)
+	}
+	if err := s.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(s); got != "[3 4]" {
+		t.Fatalf("unexpected skip result after reset: %v", got)
+	}
+
+	if _, max, _ := SizeHint(StepBy(NewNaturalGenerator(), 3)); max != -1 {
+		t.Fatalf("expected an unbounded hint, got max %v", max)
+	}
+	if _, max, exact := SizeHint(Filter(StepBy(NewNaturalGenerator(), 3), func(int) (bool, error) { return true, nil })); max != -1 || exact {
+		t.Fatalf("expected an unbounded filter hint, got (%v, %v)", max, exact)
+	}
+	st := StepBy(NewSliceIterator([]int{0, 1, 2, 3, 4, 5, 6}), 3)
+	st.Next()
+	if min, max, exact := SizeHint(st); min != 2 || max != 2 || !exact {
+		t.Fatalf("unexpected hint after the first step: (%v, %v, %v)", min, max, exact)
+	}
+
+	tw := TakeWhile(NewSliceIterator([]int{1, 2, 5, 1}), less(3))
+	if got := collect(tw); got != "[1 2]" {
+		t.Fatalf("unexpected take while result: %v", got)
+	}
+	if err := tw.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(tw); got != "[1 2]" {
+		t.Fatalf("unexpected take while result after reset: %v", got)
+	}
+
+	if v, err, ok := Last(NewSliceIterator([]int{1, 2, 3})); !ok || err != nil || v != 3 {
+		t.Fatalf("unexpected last value: %v %v %v", v, err, ok)
+	}
+	if v, err, ok := Last(nums(5)); !ok || err != nil || v != 4 {
+		t.Fatalf("unexpected last value: %v %v %v", v, err, ok)
+	}
+	if _, _, ok := Last(NewSliceIterator([]int{})); ok {
+		t.Fatal("expected no last value for an empty iterator")
+	}
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucas-s-work/funcy-go/iterator"
+)
+
+// Upper bounds in seconds of the latency histogram buckets
+var LatencyBuckets = []float64{0.00001, 0.0001, 0.001, 0.01, 0.1, 1, 10}
+
+type Stage struct {
+	Elements uint64
+	Errors   uint64
+	Drops    uint64
+	Latency  time.Duration
+	// Buckets counts the elements with a latency at or below the matching LatencyBuckets bound
+	Buckets []uint64
+}
+
+// Collector is an in-memory iterator.Observer which records totals for each stage
+type Collector struct {
+	mu     sync.Mutex
+	stages map[string]*Stage
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		stages: make(map[string]*Stage),
+	}
+}
+
+func (c *Collector) get(stage string) *Stage {
+	s, ok := c.stages[stage]
+	if !ok {
+		s = &Stage{
+			Buckets: make([]uint64, len(LatencyBuckets)),
+		}
+		c.stages[stage] = s
+	}
+
+	return s
+}
+
+func (c *Collector) ObserveElement(stage string, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.get(stage)
+	s.Elements++
+	s.Latency += latency
+	for i, bound := range LatencyBuckets {
+		if latency.Seconds() <= bound {
+			s.Buckets[i]++
+		}
+	}
+}
+
+func (c *Collector) ObserveError(stage string, _ error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.get(stage).Errors++
+}
+
+func (c *Collector) ObserveDrop(stage string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.get(stage).Drops++
+}
+
+// Stages returns a copy of the metrics recorded so far keyed by stage name
+func (c *Collector) Stages() map[string]Stage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[string]Stage, len(c.stages))
+	for name, s := range c.stages {
+		cp := *s
+		cp.Buckets = append([]uint64(nil), s.Buckets...)
+		out[name] = cp
+	}
+
+	return out
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus renders the collected metrics in the Prometheus text exposition format
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	stages := c.Stages()
+	names := make([]string, 0, len(stages))
+	for name := range stages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	counter := func(metric, help string, value func(Stage) uint64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", metric, help, metric)
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s{stage=\"%s\"} %d\n", metric, labelEscaper.Replace(name), value(stages[name]))
+		}
+	}
+
+	counter("funcy_stage_elements_total", "Values produced by a pipeline stage.", func(s Stage) uint64 { return s.Elements })
+	counter("funcy_stage_errors_total", "Errors produced by a pipeline stage.", func(s Stage) uint64 { return s.Errors })
+	counter("funcy_stage_dropped_total", "Values discarded by a pipeline stage.", func(s Stage) uint64 { return s.Drops })
+
+	metric := "funcy_stage_latency_seconds"
+	fmt.Fprintf(&b, "# HELP %s Time taken by a pipeline stage to produce a value.\n# TYPE %s histogram\n", metric, metric)
+	for _, name := range names {
+		s, label := stages[name], labelEscaper.Replace(name)
+		for i, bound := range LatencyBuckets {
+			fmt.Fprintf(&b, "%s_bucket{stage=\"%s\",le=\"%g\"} %d\n", metric, label, bound, s.Buckets[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket{stage=\"%s\",le=\"+Inf\"} %d\n", metric, label, s.Elements)
+		fmt.Fprintf(&b, "%s_sum{stage=\"%s\"} %g\n", metric, label, s.Latency.Seconds())
+		fmt.Fprintf(&b, "%s_count{stage=\"%s\"} %d\n", metric, label, s.Elements)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP exposes the collected metrics for scraping
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := c.WritePrometheus(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var _ iterator.Observer = &Collector{}
+var _ http.Handler = &Collector{}
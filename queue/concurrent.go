@@ -0,0 +1,146 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("queue is closed")
+
+// ConcurrentQueue is a Queue which is safe for use from multiple goroutines, Pop blocks until a
+// value arrives or the queue is closed. The zero value is an unbounded open queue.
+type ConcurrentQueue[V any] struct {
+	mu       sync.Mutex
+	items    Queue[V]
+	capacity int
+	closed   bool
+	// changed is closed and replaced whenever a value is pushed or popped, or the queue is closed
+	changed chan struct{}
+}
+
+// NewConcurrent creates a queue holding at most capacity values, Push blocks while it is full. A
+// capacity of 0 leaves the queue unbounded.
+func NewConcurrent[V any](capacity int) *ConcurrentQueue[V] {
+	return &ConcurrentQueue[V]{
+		capacity: capacity,
+	}
+}
+
+// wait returns a channel which is closed the next time the queue changes, the lock must be held
+func (q *ConcurrentQueue[V]) wait() <-chan struct{} {
+	if q.changed == nil {
+		q.changed = make(chan struct{})
+	}
+
+	return q.changed
+}
+
+func (q *ConcurrentQueue[V]) notify() {
+	if q.changed != nil {
+		close(q.changed)
+		q.changed = nil
+	}
+}
+
+// Push adds v to the queue, waiting for space if it is full. ErrClosed is returned if the queue is
+// closed before v could be added.
+func (q *ConcurrentQueue[V]) Push(v V) error {
+	q.mu.Lock()
+	for {
+		if q.closed {
+			q.mu.Unlock()
+			return ErrClosed
+		}
+		if q.capacity <= 0 || q.items.Len() < q.capacity {
+			break
+		}
+
+		changed := q.wait()
+		q.mu.Unlock()
+		<-changed
+		q.mu.Lock()
+	}
+
+	q.items.Push(v)
+	q.notify()
+	q.mu.Unlock()
+
+	return nil
+}
+
+// pop takes the value at the front of the queue, waiting until timeout fires if block is set. A
+// nil timeout waits until a value arrives or the queue is closed.
+func (q *ConcurrentQueue[V]) pop(block bool, timeout <-chan time.Time) (V, bool) {
+	q.mu.Lock()
+	for {
+		if v, ok := q.items.Pop(); ok {
+			q.notify()
+			q.mu.Unlock()
+			return v, true
+		}
+		if q.closed || !block {
+			q.mu.Unlock()
+
+			var o V
+			return o, false
+		}
+
+		changed := q.wait()
+		q.mu.Unlock()
+		select {
+		case <-changed:
+		case <-timeout:
+			var o V
+			return o, false
+		}
+		q.mu.Lock()
+	}
+}
+
+// Pop waits for a value, returning false once the queue is closed and empty
+func (q *ConcurrentQueue[V]) Pop() (V, bool) {
+	return q.pop(true, nil)
+}
+
+// TryPop returns a value only if one is immediately available
+func (q *ConcurrentQueue[V]) TryPop() (V, bool) {
+	return q.pop(false, nil)
+}
+
+// PopTimeout waits up to d for a value
+func (q *ConcurrentQueue[V]) PopTimeout(d time.Duration) (V, bool) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	return q.pop(true, t.C)
+}
+
+// Close stops any further values being pushed and wakes every waiting goroutine, values already in
+// the queue can still be popped.
+func (q *ConcurrentQueue[V]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.notify()
+}
+
+func (q *ConcurrentQueue[V]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.items.Len()
+}
+
+// Implement the iterator interface, iteration blocks until values arrive and ends once the queue
+// is closed and drained. Closing is left to the producer so consumers stopping early never close it.
+func (q *ConcurrentQueue[V]) Next() (V, error, bool) {
+	v, ok := q.Pop()
+	return v, nil, ok
+}
+
+func (q *ConcurrentQueue[V]) Reset() error {
+	return fmt.Errorf("cannot reset concurrent queue type")
+}
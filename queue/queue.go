@@ -6,6 +6,7 @@ const indexLim = 100
 
 type Queue[V any] struct {
 	start, end *Node[V]
+	size       int
 }
 
 type Node[V any] struct {
@@ -17,6 +18,7 @@ func (q *Queue[V]) Push(v V) {
 	n := &Node[V]{
 		el: v,
 	}
+	q.size++
 	if q.start == nil {
 		q.start = n
 		q.end = q.start
@@ -35,6 +37,7 @@ func (q *Queue[V]) Pop() (V, bool) {
 	}
 
 	end := q.end
+	q.size--
 	if q.end == q.start {
 		q.end = nil
 		q.start = nil
@@ -45,6 +48,48 @@ func (q *Queue[V]) Pop() (V, bool) {
 	return end.el, true
 }
 
+// PushFront places v at the front of the queue so that it is the next value popped
+func (q *Queue[V]) PushFront(v V) {
+	n := &Node[V]{
+		el:   v,
+		prev: q.end,
+	}
+	q.size++
+	if q.end == nil {
+		q.start = n
+	}
+
+	q.end = n
+}
+
+// Peek returns the value at the front of the queue without removing it
+func (q *Queue[V]) Peek() (V, bool) {
+	if q.end == nil {
+		var o V
+		return o, false
+	}
+
+	return q.end.el, true
+}
+
+// PeekN returns up to the first n values in the queue without removing them
+func (q *Queue[V]) PeekN(n int) []V {
+	if n > q.size {
+		n = q.size
+	}
+
+	out := make([]V, 0, n)
+	for node := q.end; node != nil && len(out) < n; node = node.prev {
+		out = append(out, node.el)
+	}
+
+	return out
+}
+
+func (q *Queue[V]) Len() int {
+	return q.size
+}
+
 // Implement the iterator interface
 func (q *Queue[V]) Next() (V, error, bool) {
 	v, ok := q.Pop()
@@ -0,0 +1,61 @@
+package tuple
+
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+func NewPair[A, B any](a A, b B) Pair[A, B] {
+	return Pair[A, B]{
+		First:  a,
+		Second: b,
+	}
+}
+
+func (p Pair[A, B]) Unpack() (A, B) {
+	return p.First, p.Second
+}
+
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+func NewTriple[A, B, C any](a A, b B, c C) Triple[A, B, C] {
+	return Triple[A, B, C]{
+		First:  a,
+		Second: b,
+		Third:  c,
+	}
+}
+
+func (t Triple[A, B, C]) Unpack() (A, B, C) {
+	return t.First, t.Second, t.Third
+}
+
+// Option holds a value which may be absent, Ok reports whether it is present
+type Option[V any] struct {
+	Value V
+	Ok    bool
+}
+
+func Some[V any](v V) Option[V] {
+	return Option[V]{
+		Value: v,
+		Ok:    true,
+	}
+}
+
+func None[V any]() Option[V] {
+	return Option[V]{}
+}
+
+// Or returns the value if present, otherwise fill
+func (o Option[V]) Or(fill V) V {
+	if o.Ok {
+		return o.Value
+	}
+
+	return fill
+}